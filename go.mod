@@ -5,7 +5,12 @@ go 1.15
 require (
 	github.com/IBM/go-sdk-core/v5 v5.4.3
 	github.com/go-openapi/strfmt v0.20.1
+	github.com/hashicorp/go-retryablehttp v0.6.6
 	github.com/onsi/ginkgo v1.16.2
 	github.com/onsi/gomega v1.12.0
-	github.com/stretchr/testify v1.6.1
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,409 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Constants associated with the BranchRenameChange.Source property.
+const (
+	BranchRenameChangeSourceInputConst      = "input"
+	BranchRenameChangeSourceTriggerConst    = "trigger"
+	BranchRenameChangeSourceDefinitionConst = "definition"
+)
+
+// Constants associated with the BranchRenameStep.Status property.
+const (
+	BranchRenameStepStatusPlannedConst        = "planned"
+	BranchRenameStepStatusAppliedConst        = "applied"
+	BranchRenameStepStatusFailedConst         = "failed"
+	BranchRenameStepStatusRolledBackConst     = "rolled back"
+	BranchRenameStepStatusRollbackFailedConst = "rollback failed"
+)
+
+// RenameRepoBranchResult : The plan of RenameRepoBranch and the outcome of each step
+type RenameRepoBranchResult struct {
+	RepoURL string
+
+	OldBranch string
+
+	NewBranch string
+
+	// Steps in the order they are applied.
+	Steps []BranchRenameStep
+
+	// Toolchains that could not be scanned and are not part of the plan.
+	Errors []AuditError
+}
+
+// BranchRenameStep : A single update renaming the branch in a pipeline
+// Inputs and triggers of a pipeline are renamed together with PatchTektonPipeline, each definition is renamed with
+// UpdateTektonPipelineDefinition.
+type BranchRenameStep struct {
+	Toolchain ToolchainLocator
+
+	PipelineID string
+
+	// Shard definition ID of the definition updated, empty for the step patching the pipeline.
+	DefinitionID string
+
+	Changes []BranchRenameChange
+
+	// One of the BranchRenameStepStatus constants.
+	Status string
+
+	// Error applying or rolling back the step.
+	Error error
+
+	// Source of the definition, for definition steps.
+	scmSource *TektonPipelineInputScmSource
+}
+
+// BranchRenameChange : An input, trigger or definition using the old branch of the repository
+type BranchRenameChange struct {
+	// One of the BranchRenameChangeSource constants.
+	Source string
+
+	// Trigger ID, shard definition ID, or service instance ID of the input.
+	ID string
+
+	// Repository URL as configured, or of the tool integration the input or trigger references.
+	RepoURL string
+}
+
+// Applied returns true if every step was applied
+func (result *RenameRepoBranchResult) Applied() bool {
+	for _, step := range result.Steps {
+		if step.Status != BranchRenameStepStatusAppliedConst {
+			return false
+		}
+	}
+	return true
+}
+
+// Changes returns the number of inputs, triggers and definitions to rename
+func (result *RenameRepoBranchResult) Changes() int {
+	changes := 0
+	for _, step := range result.Steps {
+		changes += len(step.Changes)
+	}
+	return changes
+}
+
+// Print writes the plan and the status of each step to w
+func (result *RenameRepoBranchResult) Print(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Rename branch %s to %s in %s\n", result.OldBranch, result.NewBranch, result.RepoURL); err != nil {
+		return err
+	}
+	for _, step := range result.Steps {
+		line := fmt.Sprintf("%s %s pipeline %s", step.Toolchain.Region, step.Toolchain.GUID, step.PipelineID)
+		if step.DefinitionID != "" {
+			line += " definition " + step.DefinitionID
+		}
+		line += ": " + step.Status
+		if step.Error != nil {
+			line += ", " + step.Error.Error()
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, change := range step.Changes {
+			if _, err := fmt.Fprintf(w, "  %s %s: %s\n", change.Source, change.ID, change.RepoURL); err != nil {
+				return err
+			}
+		}
+	}
+	for _, scanErr := range result.Errors {
+		if _, err := fmt.Fprintf(w, "%s %s: error: %s\n", scanErr.Toolchain.Region, scanErr.Toolchain.GUID, scanErr.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planBranchRename returns the steps renaming the branch of the repository in the pipelines of a toolchain graph.
+// Inputs and triggers without URL use the repository of the tool integration they reference.
+func planBranchRename(graph *ToolchainGraph, locator ToolchainLocator, repoURL string, oldBranch string) []BranchRenameStep {
+	steps := []BranchRenameStep{}
+	normalized := NormalizeRepoURL(repoURL)
+	uses := func(resolved string, branch *string) bool {
+		return resolved != "" && NormalizeRepoURL(resolved) == normalized && stringValue(branch) == oldBranch
+	}
+
+	for _, pipeline := range graph.Pipelines {
+		patch := BranchRenameStep{Toolchain: locator, PipelineID: stringValue(pipeline.ID), Status: BranchRenameStepStatusPlannedConst}
+		definitions := []BranchRenameStep{}
+		for i := range pipeline.Inputs {
+			input := &pipeline.Inputs[i]
+			if input.ScmSource == nil {
+				continue
+			}
+			resolved := graph.repoURL(input.ScmSource.URL, input.ServiceInstanceID)
+			if !uses(resolved, input.ScmSource.Branch) {
+				continue
+			}
+			if definitionID := stringValue(input.ShardDefinitionID); definitionID != "" {
+				definitions = append(definitions, BranchRenameStep{
+					Toolchain:    locator,
+					PipelineID:   patch.PipelineID,
+					DefinitionID: definitionID,
+					Changes:      []BranchRenameChange{{Source: BranchRenameChangeSourceDefinitionConst, ID: definitionID, RepoURL: resolved}},
+					Status:       BranchRenameStepStatusPlannedConst,
+					scmSource:    input.ScmSource,
+				})
+				continue
+			}
+			patch.Changes = append(patch.Changes, BranchRenameChange{
+				Source:  BranchRenameChangeSourceInputConst,
+				ID:      stringValue(input.ServiceInstanceID),
+				RepoURL: resolved,
+			})
+		}
+		for i := range pipeline.Triggers {
+			trigger := &pipeline.Triggers[i]
+			if trigger.ScmSource == nil {
+				continue
+			}
+			if resolved := graph.repoURL(trigger.ScmSource.URL, trigger.ServiceInstanceID); uses(resolved, trigger.ScmSource.Branch) {
+				patch.Changes = append(patch.Changes, BranchRenameChange{
+					Source:  BranchRenameChangeSourceTriggerConst,
+					ID:      stringValue(trigger.ID),
+					RepoURL: resolved,
+				})
+			}
+		}
+
+		if len(patch.Changes) > 0 {
+			steps = append(steps, patch)
+		}
+		steps = append(steps, definitions...)
+	}
+	return steps
+}
+
+// renamePipelineBranches sets the branch of the inputs and triggers of the step that use the from branch to the to
+// branch on patchTektonPipelineOptions.
+func renamePipelineBranches(step *BranchRenameStep, pipeline *TektonPipeline, patchTektonPipelineOptions *PatchTektonPipelineOptions, from string, to string) {
+	inputs := map[string]bool{}
+	triggers := map[string]bool{}
+	for _, change := range step.Changes {
+		switch change.Source {
+		case BranchRenameChangeSourceInputConst:
+			inputs[change.ID] = true
+		case BranchRenameChangeSourceTriggerConst:
+			triggers[change.ID] = true
+		}
+	}
+
+	if len(inputs) > 0 {
+		patchTektonPipelineOptions.Inputs = make([]TektonPipelineInput, len(pipeline.Inputs))
+		for i, input := range pipeline.Inputs {
+			if input.ScmSource != nil && input.ShardDefinitionID == nil && inputs[stringValue(input.ServiceInstanceID)] && stringValue(input.ScmSource.Branch) == from {
+				scmSource := *input.ScmSource
+				scmSource.Branch = core.StringPtr(to)
+				input.ScmSource = &scmSource
+			}
+			patchTektonPipelineOptions.Inputs[i] = input
+		}
+	}
+	if len(triggers) > 0 {
+		patchTektonPipelineOptions.Triggers = make([]TektonPipelineTrigger, len(pipeline.Triggers))
+		for i, trigger := range pipeline.Triggers {
+			if trigger.ScmSource != nil && triggers[stringValue(trigger.ID)] && stringValue(trigger.ScmSource.Branch) == from {
+				scmSource := *trigger.ScmSource
+				scmSource.Branch = core.StringPtr(to)
+				trigger.ScmSource = &scmSource
+			}
+			patchTektonPipelineOptions.Triggers[i] = trigger
+		}
+	}
+}
+
+// renameBranch renames the branch from the from branch to the to branch for a single step.
+func (openToolchain *OpenToolchainV1) renameBranch(ctx context.Context, step *BranchRenameStep, from string, to string, headers map[string]string) error {
+	if step.DefinitionID != "" {
+		scmSource := &CreateTektonPipelineDefinitionParamsInputsItemScmSource{}
+		if step.scmSource != nil {
+			scmSource.Path = step.scmSource.Path
+			scmSource.URL = step.scmSource.URL
+			scmSource.Type = step.scmSource.Type
+			scmSource.BlindConnection = step.scmSource.BlindConnection
+		}
+		scmSource.Branch = core.StringPtr(to)
+
+		updateTektonPipelineDefinitionOptions := openToolchain.NewUpdateTektonPipelineDefinitionOptions(step.PipelineID, step.DefinitionID, step.Toolchain.Region, step.Toolchain.EnvID)
+		updateTektonPipelineDefinitionOptions.SetScmSource(scmSource)
+		updateTektonPipelineDefinitionOptions.SetHeaders(headers)
+		_, _, err := openToolchain.UpdateTektonPipelineDefinitionWithContext(ctx, updateTektonPipelineDefinitionOptions)
+		return err
+	}
+
+	retryOnConflictOptions := openToolchain.NewRetryOnConflictOptions(step.PipelineID, step.Toolchain.Region, func(pipeline *TektonPipeline, patchTektonPipelineOptions *PatchTektonPipelineOptions) error {
+		renamePipelineBranches(step, pipeline, patchTektonPipelineOptions, from, to)
+		return nil
+	})
+	retryOnConflictOptions.SetHeaders(headers)
+	_, _, err := openToolchain.RetryOnConflictWithContext(ctx, retryOnConflictOptions)
+	return err
+}
+
+// RenameRepoBranch : Rename the branch of a repository in the pipelines of a set of toolchains
+// Every pipeline input, trigger and definition using the old branch of the repository, compared after
+// NormalizeRepoURL, is planned to use the new branch. Toolchains that cannot be scanned are recorded in the result
+// errors. In dry-run mode the plan is returned without updating anything. Otherwise the steps are applied in order
+// and, when one fails, the steps already applied are rolled back in reverse order and the error of the failed step is
+// returned along with the result. The rollback is not interrupted when the context is done.
+func (openToolchain *OpenToolchainV1) RenameRepoBranch(renameRepoBranchOptions *RenameRepoBranchOptions) (result *RenameRepoBranchResult, err error) {
+	return openToolchain.RenameRepoBranchWithContext(context.Background(), renameRepoBranchOptions)
+}
+
+// RenameRepoBranchWithContext is an alternate form of the RenameRepoBranch method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) RenameRepoBranchWithContext(ctx context.Context, renameRepoBranchOptions *RenameRepoBranchOptions) (result *RenameRepoBranchResult, err error) {
+	err = core.ValidateNotNil(renameRepoBranchOptions, "renameRepoBranchOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(renameRepoBranchOptions, "renameRepoBranchOptions")
+	if err != nil {
+		return
+	}
+
+	oldBranch := *renameRepoBranchOptions.OldBranch
+	newBranch := *renameRepoBranchOptions.NewBranch
+	headers := renameRepoBranchOptions.Headers
+	result = &RenameRepoBranchResult{
+		RepoURL:   *renameRepoBranchOptions.RepoURL,
+		OldBranch: oldBranch,
+		NewBranch: newBranch,
+		Steps:     []BranchRenameStep{},
+		Errors:    []AuditError{},
+	}
+	for _, locator := range renameRepoBranchOptions.Toolchains {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		getToolchainGraphOptions := openToolchain.NewGetToolchainGraphOptions(locator.Region, locator.GUID, locator.EnvID)
+		getToolchainGraphOptions.SetHeaders(headers)
+		graph, graphErr := openToolchain.GetToolchainGraphWithContext(ctx, getToolchainGraphOptions)
+		if graphErr != nil {
+			result.Errors = append(result.Errors, AuditError{Toolchain: locator, Message: graphErr.Error()})
+			continue
+		}
+		result.Steps = append(result.Steps, planBranchRename(graph, locator, result.RepoURL, oldBranch)...)
+	}
+	if renameRepoBranchOptions.DryRun != nil && *renameRepoBranchOptions.DryRun {
+		return
+	}
+
+	for i := range result.Steps {
+		step := &result.Steps[i]
+		if err = ctx.Err(); err == nil {
+			err = openToolchain.renameBranch(ctx, step, oldBranch, newBranch, headers)
+		}
+		if err == nil {
+			step.Status = BranchRenameStepStatusAppliedConst
+			continue
+		}
+
+		step.Status = BranchRenameStepStatusFailedConst
+		step.Error = err
+		err = fmt.Errorf("error renaming branch in pipeline %s: %s", step.PipelineID, err.Error())
+		for j := i - 1; j >= 0; j-- {
+			applied := &result.Steps[j]
+			if rollbackErr := openToolchain.renameBranch(context.Background(), applied, newBranch, oldBranch, headers); rollbackErr != nil {
+				applied.Status = BranchRenameStepStatusRollbackFailedConst
+				applied.Error = rollbackErr
+			} else {
+				applied.Status = BranchRenameStepStatusRolledBackConst
+			}
+		}
+		return
+	}
+
+	return
+}
+
+// RenameRepoBranchOptions : The RenameRepoBranch options.
+type RenameRepoBranchOptions struct {
+	// URL of the repository, in HTTPS or SSH form.
+	RepoURL *string `validate:"required,ne="`
+
+	OldBranch *string `validate:"required,ne="`
+
+	NewBranch *string `validate:"required,ne="`
+
+	// Toolchains to scan.
+	Toolchains []ToolchainLocator `validate:"required,min=1"`
+
+	// Return the plan without updating anything.
+	DryRun *bool
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRenameRepoBranchOptions : Instantiate RenameRepoBranchOptions
+func (*OpenToolchainV1) NewRenameRepoBranchOptions(repoURL string, oldBranch string, newBranch string, toolchains []ToolchainLocator) *RenameRepoBranchOptions {
+	return &RenameRepoBranchOptions{
+		RepoURL:    core.StringPtr(repoURL),
+		OldBranch:  core.StringPtr(oldBranch),
+		NewBranch:  core.StringPtr(newBranch),
+		Toolchains: toolchains,
+	}
+}
+
+// SetRepoURL : Allow user to set RepoURL
+func (options *RenameRepoBranchOptions) SetRepoURL(repoURL string) *RenameRepoBranchOptions {
+	options.RepoURL = core.StringPtr(repoURL)
+	return options
+}
+
+// SetOldBranch : Allow user to set OldBranch
+func (options *RenameRepoBranchOptions) SetOldBranch(oldBranch string) *RenameRepoBranchOptions {
+	options.OldBranch = core.StringPtr(oldBranch)
+	return options
+}
+
+// SetNewBranch : Allow user to set NewBranch
+func (options *RenameRepoBranchOptions) SetNewBranch(newBranch string) *RenameRepoBranchOptions {
+	options.NewBranch = core.StringPtr(newBranch)
+	return options
+}
+
+// SetToolchains : Allow user to set Toolchains
+func (options *RenameRepoBranchOptions) SetToolchains(toolchains []ToolchainLocator) *RenameRepoBranchOptions {
+	options.Toolchains = toolchains
+	return options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (options *RenameRepoBranchOptions) SetDryRun(dryRun bool) *RenameRepoBranchOptions {
+	options.DryRun = core.BoolPtr(dryRun)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RenameRepoBranchOptions) SetHeaders(param map[string]string) *RenameRepoBranchOptions {
+	options.Headers = param
+	return options
+}
@@ -0,0 +1,205 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RenameRepoBranch`, func() {
+	var testServer *httptest.Server
+	var pipeline map[string]interface{}
+	var patches, definitionUpdates []string
+	var failDefinitionUpdates bool
+
+	// branches returns the branch of each input, then of each trigger of the pipeline.
+	branches := func() []string {
+		result := []string{}
+		for _, key := range []string{"inputs", "triggers"} {
+			for _, item := range pipeline[key].([]interface{}) {
+				branch := ""
+				if scmSource, ok := item.(map[string]interface{})["scmSource"].(map[string]interface{}); ok {
+					branch, _ = scmSource["branch"].(string)
+				}
+				result = append(result, branch)
+			}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		patches, definitionUpdates = nil, nil
+		failDefinitionUpdates = false
+		Expect(json.Unmarshal([]byte(`{"id": "pl1", "name": "pl1", "toolchainId": "tc1", "envProperties": [], "updated_at_timestamp": 1000,
+			"inputs": [
+				{"serviceInstanceId": "repo1", "scmSource": {"branch": "master", "path": ".tekton"}},
+				{"serviceInstanceId": "repo1", "shardDefinitionId": "s1", "scmSource": {"url": "git@github.com:org/app.git", "branch": "master", "path": ".tekton/ci"}},
+				{"serviceInstanceId": "repo2", "scmSource": {"url": "https://github.com/org/other", "branch": "master"}}
+			],
+			"triggers": [
+				{"id": "t1", "eventListener": "l", "type": "scm", "serviceInstanceId": "repo1", "scmSource": {"branch": "master"}},
+				{"id": "t2", "eventListener": "l", "type": "scm", "scmSource": {"url": "https://github.com/org/app", "branch": "develop"}},
+				{"id": "t3", "eventListener": "l", "type": "manual"}
+			]}`), &pipeline)).To(Succeed())
+
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			path := req.URL.Path
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "services": [
+					{"service_id": "pipeline", "instance_id": "pl1", "parameters": {"type": "tekton"}},
+					{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"repo_url": "https://github.com/Org/App.git"}},
+					{"service_id": "githubconsolidated", "instance_id": "repo2", "parameters": {"repo_url": "https://github.com/org/other"}}
+				]}]}`)
+			case req.Method == "GET" && path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1":
+				res.WriteHeader(200)
+				Expect(json.NewEncoder(res).Encode(pipeline)).To(Succeed())
+			case req.Method == "PATCH" && path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/config":
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				for _, key := range []string{"inputs", "triggers"} {
+					if items, ok := body[key]; ok {
+						pipeline[key] = items
+					}
+				}
+				pipeline["updated_at_timestamp"] = pipeline["updated_at_timestamp"].(float64) + 1
+				patches = append(patches, strings.Join(branches(), ","))
+				res.WriteHeader(200)
+				Expect(json.NewEncoder(res).Encode(pipeline)).To(Succeed())
+			case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"pipelineId": "pl1", "id": "d1", "shardRepos": [{"shardDefinitionId": "s1", "repoUrl": "https://github.com/org/app"}]}`)
+			case req.Method == "PUT" && path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition/s1":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				var body map[string]map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				Expect(body["scmSource"]["path"]).To(Equal(".tekton/ci"))
+				definitionUpdates = append(definitionUpdates, body["scmSource"]["branch"].(string))
+				if failDefinitionUpdates {
+					res.WriteHeader(500)
+					fmt.Fprint(res, `{"message": "internal error"}`)
+					return
+				}
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "s1"}`)
+			default:
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	newRenameRepoBranchOptions := func(openToolchainService *opentoolchainv1.OpenToolchainV1) *opentoolchainv1.RenameRepoBranchOptions {
+		return openToolchainService.NewRenameRepoBranchOptions("https://github.com/org/app", "master", "main", []opentoolchainv1.ToolchainLocator{
+			{Region: "us-south", GUID: "tc1", EnvID: "ibm:yp:us-south"},
+			{Region: "us-south", GUID: "tc2", EnvID: "ibm:yp:us-south"},
+		})
+	}
+
+	It(`Invoke RenameRepoBranch in dry-run mode`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, err := openToolchainService.RenameRepoBranch(newRenameRepoBranchOptions(openToolchainService).SetDryRun(true))
+		Expect(err).To(BeNil())
+		Expect(result.Steps).To(HaveLen(2))
+		Expect(result.Changes()).To(Equal(3))
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.Errors[0].Toolchain.GUID).To(Equal("tc2"))
+		Expect(result.Applied()).To(BeFalse())
+		Expect(patches).To(BeEmpty())
+		Expect(definitionUpdates).To(BeEmpty())
+
+		var output bytes.Buffer
+		Expect(result.Print(&output)).To(Succeed())
+		Expect(output.String()).To(HavePrefix(`Rename branch master to main in https://github.com/org/app
+us-south tc1 pipeline pl1: planned
+  input repo1: https://github.com/Org/App.git
+  trigger t1: https://github.com/Org/App.git
+us-south tc1 pipeline pl1 definition s1: planned
+  definition s1: git@github.com:org/app.git
+us-south tc2: error: `))
+	})
+	It(`Invoke RenameRepoBranch successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, err := openToolchainService.RenameRepoBranch(newRenameRepoBranchOptions(openToolchainService))
+		Expect(err).To(BeNil())
+		Expect(result.Applied()).To(BeTrue())
+		Expect(result.Steps[0].Status).To(Equal(opentoolchainv1.BranchRenameStepStatusAppliedConst))
+		Expect(result.Steps[1].Status).To(Equal(opentoolchainv1.BranchRenameStepStatusAppliedConst))
+		Expect(patches).To(Equal([]string{"main,master,master,main,develop,"}))
+		Expect(definitionUpdates).To(Equal([]string{"main"}))
+	})
+	It(`Invoke RenameRepoBranch with error: rollback on partial failure`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		failDefinitionUpdates = true
+		result, err := openToolchainService.RenameRepoBranch(newRenameRepoBranchOptions(openToolchainService))
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("pipeline pl1"))
+		Expect(result.Applied()).To(BeFalse())
+		Expect(result.Steps[0].Status).To(Equal(opentoolchainv1.BranchRenameStepStatusRolledBackConst))
+		Expect(result.Steps[1].Status).To(Equal(opentoolchainv1.BranchRenameStepStatusFailedConst))
+		Expect(result.Steps[1].Error).ToNot(BeNil())
+		Expect(patches).To(Equal([]string{"main,master,master,main,develop,", "master,master,master,master,develop,"}))
+		Expect(definitionUpdates).To(Equal([]string{"main"}))
+	})
+	It(`Invoke RenameRepoBranch with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, err := openToolchainService.RenameRepoBranch(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+
+		result, err = openToolchainService.RenameRepoBranch(newRenameRepoBranchOptions(openToolchainService).SetNewBranch(""))
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
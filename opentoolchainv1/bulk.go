@@ -0,0 +1,295 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+const (
+	defaultBulkConcurrency = 5
+	defaultBulkMaxRetries  = 3
+
+	// Wait applied after a 429 response without a usable Retry-After header. 503 responses are only retried when they
+	// have one.
+	defaultRetryAfter = time.Second
+)
+
+// BulkOperation : An operation applied to a single target of a bulk execution
+type BulkOperation func(ctx context.Context, openToolchain *OpenToolchainV1, target string) (*core.DetailedResponse, error)
+
+// BulkTargetResult : The outcome of a bulk operation for one target
+type BulkTargetResult struct {
+	Target string
+
+	// Response of the last attempt, if any.
+	Response *core.DetailedResponse
+
+	Error error
+
+	// Number of times the operation was invoked for the target.
+	Attempts int
+
+	// Whether the target was skipped or interrupted because the context was done.
+	Canceled bool
+
+	Duration time.Duration
+}
+
+// Succeeded returns true if the operation completed without error for the target
+func (result *BulkTargetResult) Succeeded() bool {
+	return result.Attempts > 0 && result.Error == nil
+}
+
+// BulkSummary : Totals of a bulk execution
+type BulkSummary struct {
+	Total int
+
+	Succeeded int
+
+	Failed int
+
+	Canceled int
+
+	Duration time.Duration
+}
+
+// BulkResult : The outcome of a bulk execution
+type BulkResult struct {
+	// Per-target results, in the order of ExecuteBulkOptions.Targets.
+	Results []BulkTargetResult
+
+	Summary BulkSummary
+}
+
+// Failures returns the results of the targets that failed or were canceled
+func (result *BulkResult) Failures() []BulkTargetResult {
+	failures := []BulkTargetResult{}
+	for _, targetResult := range result.Results {
+		if !targetResult.Succeeded() {
+			failures = append(failures, targetResult)
+		}
+	}
+	return failures
+}
+
+// ExecuteBulk : Apply an operation to many targets concurrently
+// Targets are processed by a bounded pool of workers. A 429 response, or a 503 response with a Retry-After header,
+// pauses the rate limiter of the service for the duration given by its Retry-After header and the target is retried,
+// up to MaxRetries times. Without a rate limiter set on the service, the pause applies to the workers of the
+// execution. Errors are recorded per target and do not stop the execution; once the context is done, the targets not
+// yet started are reported as canceled.
+func (openToolchain *OpenToolchainV1) ExecuteBulk(executeBulkOptions *ExecuteBulkOptions) (result *BulkResult, err error) {
+	return openToolchain.ExecuteBulkWithContext(context.Background(), executeBulkOptions)
+}
+
+// ExecuteBulkWithContext is an alternate form of the ExecuteBulk method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) ExecuteBulkWithContext(ctx context.Context, executeBulkOptions *ExecuteBulkOptions) (result *BulkResult, err error) {
+	err = core.ValidateNotNil(executeBulkOptions, "executeBulkOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(executeBulkOptions, "executeBulkOptions")
+	if err != nil {
+		return
+	}
+
+	concurrency := defaultBulkConcurrency
+	if executeBulkOptions.Concurrency != nil && *executeBulkOptions.Concurrency > 0 {
+		concurrency = int(*executeBulkOptions.Concurrency)
+	}
+	maxRetries := defaultBulkMaxRetries
+	if executeBulkOptions.MaxRetries != nil && *executeBulkOptions.MaxRetries >= 0 {
+		maxRetries = int(*executeBulkOptions.MaxRetries)
+	}
+
+	start := time.Now()
+	targets := executeBulkOptions.Targets
+	result = &BulkResult{
+		Results: make([]BulkTargetResult, len(targets)),
+	}
+	for i, target := range targets {
+		result.Results[i].Target = target
+	}
+
+	limiter := openToolchain.rateLimiter
+	if limiter == nil {
+		limiter = NewRateLimiter(0, 1)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(targets); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				openToolchain.executeBulkTarget(ctx, executeBulkOptions.Operation, limiter, maxRetries, &result.Results[i])
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for dispatched < len(targets) && ctx.Err() == nil {
+		select {
+		case jobs <- dispatched:
+			dispatched++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := dispatched; i < len(targets); i++ {
+		result.Results[i].Error = ctx.Err()
+		result.Results[i].Canceled = true
+	}
+
+	result.Summary.Total = len(targets)
+	for i := range result.Results {
+		targetResult := &result.Results[i]
+		switch {
+		case targetResult.Canceled:
+			result.Summary.Canceled++
+		case targetResult.Succeeded():
+			result.Summary.Succeeded++
+		default:
+			result.Summary.Failed++
+		}
+	}
+	result.Summary.Duration = time.Since(start)
+
+	return
+}
+
+// executeBulkTarget runs the operation for a single target, retrying while the service responds with 429, or 503
+// with a Retry-After header. The limiter is paused before each retry; requests take their tokens from the limiter of
+// the service, so the workers only wait here for the pause to end.
+func (openToolchain *OpenToolchainV1) executeBulkTarget(ctx context.Context, operation BulkOperation, limiter *RateLimiter, maxRetries int, targetResult *BulkTargetResult) {
+	start := time.Now()
+	defer func() {
+		targetResult.Duration = time.Since(start)
+	}()
+
+	for {
+		if err := limiter.waitResumed(ctx); err != nil {
+			targetResult.Error = err
+			targetResult.Canceled = true
+			return
+		}
+
+		targetResult.Attempts++
+		targetResult.Response, targetResult.Error = operation(ctx, openToolchain, targetResult.Target)
+		if targetResult.Error == nil || ctx.Err() != nil {
+			targetResult.Canceled = targetResult.Error != nil
+			return
+		}
+		d, retry := bulkRetryDelay(targetResult.Response)
+		if !retry || targetResult.Attempts > maxRetries {
+			return
+		}
+		limiter.Pause(d)
+	}
+}
+
+// bulkRetryDelay returns how long to wait before retrying a failed target, and false if it is not retried.
+func bulkRetryDelay(response *core.DetailedResponse) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return retryAfter(response, defaultRetryAfter), true
+	case http.StatusServiceUnavailable:
+		return parseRetryAfter(response.Headers)
+	}
+	return 0, false
+}
+
+// retryAfter returns the wait requested by the Retry-After header of a response, or fallback if there is none.
+func retryAfter(response *core.DetailedResponse, fallback time.Duration) time.Duration {
+	if response == nil {
+		return fallback
+	}
+	if d, ok := parseRetryAfter(response.Headers); ok {
+		return d
+	}
+	return fallback
+}
+
+// PatchTektonPipelineBulkOperation returns a BulkOperation that applies a copy of patchTektonPipelineOptions to
+// every target pipeline GUID.
+func PatchTektonPipelineBulkOperation(patchTektonPipelineOptions *PatchTektonPipelineOptions) BulkOperation {
+	return func(ctx context.Context, openToolchain *OpenToolchainV1, target string) (*core.DetailedResponse, error) {
+		options := *patchTektonPipelineOptions
+		options.GUID = core.StringPtr(target)
+		_, response, err := openToolchain.PatchTektonPipelineWithContext(ctx, &options)
+		return response, err
+	}
+}
+
+// ExecuteBulkOptions : The ExecuteBulk options.
+type ExecuteBulkOptions struct {
+	// Targets passed to the operation, typically pipeline or toolchain GUIDs.
+	Targets []string `validate:"required,min=1"`
+
+	Operation BulkOperation `validate:"required"`
+
+	// Maximum number of targets processed at the same time. Defaults to 5.
+	Concurrency *int64
+
+	// Maximum number of retries of a target after a 429 response. Defaults to 3.
+	MaxRetries *int64
+}
+
+// NewExecuteBulkOptions : Instantiate ExecuteBulkOptions
+func (*OpenToolchainV1) NewExecuteBulkOptions(targets []string, operation BulkOperation) *ExecuteBulkOptions {
+	return &ExecuteBulkOptions{
+		Targets:   targets,
+		Operation: operation,
+	}
+}
+
+// SetTargets : Allow user to set Targets
+func (options *ExecuteBulkOptions) SetTargets(targets []string) *ExecuteBulkOptions {
+	options.Targets = targets
+	return options
+}
+
+// SetOperation : Allow user to set Operation
+func (options *ExecuteBulkOptions) SetOperation(operation BulkOperation) *ExecuteBulkOptions {
+	options.Operation = operation
+	return options
+}
+
+// SetConcurrency : Allow user to set Concurrency
+func (options *ExecuteBulkOptions) SetConcurrency(concurrency int64) *ExecuteBulkOptions {
+	options.Concurrency = core.Int64Ptr(concurrency)
+	return options
+}
+
+// SetMaxRetries : Allow user to set MaxRetries
+func (options *ExecuteBulkOptions) SetMaxRetries(maxRetries int64) *ExecuteBulkOptions {
+	options.MaxRetries = core.Int64Ptr(maxRetries)
+	return options
+}
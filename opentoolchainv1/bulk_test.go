@@ -0,0 +1,170 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ExecuteBulk(executeBulkOptions *ExecuteBulkOptions)`, func() {
+	var testServer *httptest.Server
+	var mutex sync.Mutex
+	var calls map[string]int
+	var inFlight, maxInFlight int
+
+	BeforeEach(func() {
+		calls = make(map[string]int)
+		inFlight, maxInFlight = 0, 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("PATCH"))
+			guid := strings.TrimSuffix(req.URL.Path[strings.Index(req.URL.Path, "/tekton-pipelines/")+len("/tekton-pipelines/"):], "/config")
+
+			mutex.Lock()
+			calls[guid]++
+			count := calls[guid]
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			inFlight--
+			mutex.Unlock()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case guid == "throttled" && count == 1:
+				res.Header().Set("Retry-After", "0")
+				res.WriteHeader(429)
+				fmt.Fprint(res, `{"message": "too many requests"}`)
+			case guid == "unavailable" && count == 1:
+				res.Header().Set("Retry-After", "0")
+				res.WriteHeader(503)
+				fmt.Fprint(res, `{"message": "service unavailable"}`)
+			case guid == "down":
+				res.WriteHeader(503)
+				fmt.Fprint(res, `{"message": "service unavailable"}`)
+			case guid == "broken":
+				res.WriteHeader(500)
+				fmt.Fprint(res, `{"message": "internal error"}`)
+			default:
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"id": "%s", "name": "n", "toolchainId": "tc", "envProperties": []}`, guid)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke ExecuteBulk successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		envProperty, err := openToolchainService.NewEnvProperty("LOG_LEVEL", "debug", "TEXT")
+		Expect(err).To(BeNil())
+		patchTektonPipelineOptionsModel := openToolchainService.NewPatchTektonPipelineOptions("", "us-south")
+		patchTektonPipelineOptionsModel.SetEnvProperties([]opentoolchainv1.EnvProperty{*envProperty})
+
+		targets := []string{"p1", "p2", "throttled", "broken", "p3", "p4", "unavailable", "down"}
+		executeBulkOptionsModel := openToolchainService.NewExecuteBulkOptions(targets, opentoolchainv1.PatchTektonPipelineBulkOperation(patchTektonPipelineOptionsModel))
+		executeBulkOptionsModel.SetConcurrency(2)
+		result, err := openToolchainService.ExecuteBulk(executeBulkOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.Results).To(HaveLen(8))
+		Expect(maxInFlight).To(BeNumerically("<=", 2))
+
+		Expect(result.Summary.Total).To(Equal(8))
+		Expect(result.Summary.Succeeded).To(Equal(6))
+		Expect(result.Summary.Failed).To(Equal(2))
+		Expect(result.Summary.Canceled).To(Equal(0))
+
+		Expect(result.Results[2].Target).To(Equal("throttled"))
+		Expect(result.Results[2].Attempts).To(Equal(2))
+		Expect(result.Results[2].Succeeded()).To(BeTrue())
+
+		// 503 responses are retried only when they have a Retry-After header
+		Expect(result.Results[6].Target).To(Equal("unavailable"))
+		Expect(result.Results[6].Attempts).To(Equal(2))
+		Expect(result.Results[6].Succeeded()).To(BeTrue())
+
+		failures := result.Failures()
+		Expect(failures).To(HaveLen(2))
+		Expect(failures[0].Target).To(Equal("broken"))
+		Expect(failures[0].Response.StatusCode).To(Equal(500))
+		Expect(failures[0].Attempts).To(Equal(1))
+		Expect(failures[1].Target).To(Equal("down"))
+		Expect(failures[1].Response.StatusCode).To(Equal(503))
+		Expect(failures[1].Attempts).To(Equal(1))
+
+		// The template options are not modified
+		Expect(*patchTektonPipelineOptionsModel.GUID).To(Equal(""))
+	})
+	It(`Invoke ExecuteBulk with a canceled context`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancelFunc()
+		operation := func(ctx context.Context, openToolchain *opentoolchainv1.OpenToolchainV1, target string) (*core.DetailedResponse, error) {
+			return nil, nil
+		}
+		result, err := openToolchainService.ExecuteBulkWithContext(ctx, openToolchainService.NewExecuteBulkOptions([]string{"a", "b", "c"}, operation))
+		Expect(err).To(BeNil())
+		Expect(result.Summary.Total).To(Equal(3))
+		Expect(result.Summary.Canceled).To(Equal(3))
+		for _, targetResult := range result.Results {
+			Expect(targetResult.Error).To(Equal(context.Canceled))
+			Expect(targetResult.Attempts).To(Equal(0))
+		}
+	})
+	It(`Invoke ExecuteBulk with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, err := openToolchainService.ExecuteBulk(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+
+		result, err = openToolchainService.ExecuteBulk(openToolchainService.NewExecuteBulkOptions([]string{"a"}, nil))
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
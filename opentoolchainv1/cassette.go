@@ -0,0 +1,268 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Modes of a CassetteTransport
+const (
+	CassetteModeRecordConst = "record"
+	CassetteModeReplayConst = "replay"
+)
+
+// cassetteScrubbedHeaders are the headers that are never written to a cassette.
+var cassetteScrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Auth-Refresh-Token"}
+
+// CassetteRequest : A request recorded in a cassette
+type CassetteRequest struct {
+	Method string `json:"method"`
+
+	// Path and query of the request, the host is not recorded so that interactions can be replayed against any
+	// server.
+	URI string `json:"uri"`
+
+	Headers http.Header `json:"headers,omitempty"`
+
+	Body string `json:"body,omitempty"`
+}
+
+// CassetteResponse : A response recorded in a cassette
+type CassetteResponse struct {
+	StatusCode int `json:"status_code"`
+
+	Headers http.Header `json:"headers,omitempty"`
+
+	Body string `json:"body,omitempty"`
+}
+
+// CassetteInteraction : A request and the response received for it
+type CassetteInteraction struct {
+	Request CassetteRequest `json:"request"`
+
+	Response CassetteResponse `json:"response"`
+}
+
+// Cassette : Recorded HTTP interactions with the service
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette from a file written by Save
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := new(Cassette)
+	if err = json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("error reading cassette %s: %s", path, err.Error())
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to a file
+func (cassette *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// CassetteTransport : http.RoundTripper recording interactions with the service to a cassette or replaying them
+// In record mode, requests are sent with the base transport and every interaction is appended to the cassette with
+// its secrets scrubbed: the Authorization and cookie headers are dropped, and the api_key, api_token and
+// repository_token fields and the values of SECURE environment properties are redacted from URLs and bodies. In
+// replay mode, no request is sent; each request is answered with the first interaction of the cassette not yet
+// replayed that has the same method, URI and body, after scrubbing.
+//
+// Install the transport with SetHTTPClient after enabling or disabling retries, which replace the HTTP client:
+//
+//	openToolchainService.SetHTTPClient(&http.Client{Transport: transport})
+type CassetteTransport struct {
+	mutex sync.Mutex
+
+	mode string
+
+	cassette *Cassette
+
+	base http.RoundTripper
+
+	replayed []bool
+
+	scrubber func(interaction *CassetteInteraction)
+}
+
+// NewCassetteTransport : Instantiate CassetteTransport
+// mode is CassetteModeRecordConst or CassetteModeReplayConst; base is the transport sending the requests in record
+// mode, http.DefaultTransport when nil.
+func NewCassetteTransport(mode string, cassette *Cassette, base http.RoundTripper) (*CassetteTransport, error) {
+	if mode != CassetteModeRecordConst && mode != CassetteModeReplayConst {
+		return nil, fmt.Errorf("unsupported cassette mode: %s", mode)
+	}
+	if cassette == nil {
+		cassette = new(Cassette)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &CassetteTransport{
+		mode:     mode,
+		cassette: cassette,
+		base:     base,
+		replayed: make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// SetScrubber sets a function applied to every interaction after the built-in scrubbing, before it is recorded or
+// matched, to remove secrets specific to the caller
+func (transport *CassetteTransport) SetScrubber(scrubber func(interaction *CassetteInteraction)) {
+	transport.scrubber = scrubber
+}
+
+// Cassette returns the cassette of the transport
+func (transport *CassetteTransport) Cassette() *Cassette {
+	return transport.cassette
+}
+
+// Unplayed returns the interactions of the cassette that have not been replayed
+func (transport *CassetteTransport) Unplayed() []CassetteInteraction {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	unplayed := []CassetteInteraction{}
+	for i, interaction := range transport.cassette.Interactions {
+		if i >= len(transport.replayed) || !transport.replayed[i] {
+			unplayed = append(unplayed, interaction)
+		}
+	}
+	return unplayed
+}
+
+// RoundTrip records or replays a single interaction
+func (transport *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := readRequestBody(req)
+	interaction := CassetteInteraction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URI:     redactRequestURI(req.URL),
+			Headers: scrubHeaders(req.Header),
+			Body:    scrubBody(req.Header.Get("Content-Type"), body),
+		},
+	}
+
+	if transport.mode == CassetteModeReplayConst {
+		return transport.replay(req, interaction)
+	}
+
+	resp, err := transport.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	interaction.Response = CassetteResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    scrubHeaders(resp.Header),
+		Body:       scrubBody(resp.Header.Get("Content-Type"), responseBody),
+	}
+	if transport.scrubber != nil {
+		transport.scrubber(&interaction)
+	}
+
+	transport.mutex.Lock()
+	transport.cassette.Interactions = append(transport.cassette.Interactions, interaction)
+	transport.replayed = append(transport.replayed, true)
+	transport.mutex.Unlock()
+	return resp, nil
+}
+
+func (transport *CassetteTransport) replay(req *http.Request, interaction CassetteInteraction) (*http.Response, error) {
+	if transport.scrubber != nil {
+		transport.scrubber(&interaction)
+	}
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	for len(transport.replayed) < len(transport.cassette.Interactions) {
+		transport.replayed = append(transport.replayed, false)
+	}
+	for i, recorded := range transport.cassette.Interactions {
+		if transport.replayed[i] {
+			continue
+		}
+		if recorded.Request.Method != interaction.Request.Method || recorded.Request.URI != interaction.Request.URI ||
+			recorded.Request.Body != interaction.Request.Body {
+			continue
+		}
+		transport.replayed[i] = true
+
+		headers := http.Header{}
+		for name, values := range recorded.Response.Headers {
+			headers[name] = append([]string{}, values...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode:    recorded.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        headers,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(recorded.Response.Body))),
+			ContentLength: int64(len(recorded.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", interaction.Request.Method, interaction.Request.URI)
+}
+
+// redactRequestURI returns the path and query of a URL with the secret query parameters redacted.
+func redactRequestURI(requestURL *url.URL) string {
+	redacted := *requestURL
+	redacted.RawQuery = redactValues(requestURL.Query()).Encode()
+	return redacted.RequestURI()
+}
+
+func scrubHeaders(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range cassetteScrubbedHeaders {
+		scrubbed.Del(name)
+	}
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
+
+func scrubBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	return redactBody(contentType, body)
+}
@@ -0,0 +1,159 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`CassetteTransport`, func() {
+	var testServer *httptest.Server
+	var requests int
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cassette")
+		Expect(err).To(BeNil())
+
+		requests = 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			requests++
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/config"):
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl1", "name": "pipeline", "toolchainId": "tc1", "envProperties": [{"name": "password", "value": "secret-password", "type": "SECURE"}]}`)
+			case strings.HasSuffix(req.URL.Path, "/toolchains/missing"):
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+			default:
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_results": 1, "items": [{"toolchain_guid": "%s", "name": "toolchain"}]}`, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+		os.RemoveAll(dir)
+	})
+
+	newService := func(url string, transport http.RoundTripper) *opentoolchainv1.OpenToolchainV1 {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL: url,
+			Authenticator: &core.BearerTokenAuthenticator{
+				BearerToken: "secret-bearer-token",
+			},
+		})
+		Expect(serviceErr).To(BeNil())
+		openToolchainService.SetHTTPClient(&http.Client{Transport: transport})
+		return openToolchainService
+	}
+
+	It(`Record and replay interactions`, func() {
+		path := filepath.Join(dir, "cassette.json")
+
+		recorder, err := opentoolchainv1.NewCassetteTransport(opentoolchainv1.CassetteModeRecordConst, nil, nil)
+		Expect(err).To(BeNil())
+		openToolchainService := newService(testServer.URL, recorder)
+
+		envProperty, err := openToolchainService.NewEnvProperty("password", "secret-password", opentoolchainv1.EnvPropertyTypeSecureConst)
+		Expect(err).To(BeNil())
+		patchTektonPipelineOptionsModel := openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south")
+		patchTektonPipelineOptionsModel.SetEnvProperties([]opentoolchainv1.EnvProperty{*envProperty})
+
+		recordedToolchain, _, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		_, _, err = openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(err).To(BeNil())
+		_, response, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "missing"))
+		Expect(err).ToNot(BeNil())
+		Expect(response.StatusCode).To(Equal(404))
+		Expect(requests).To(Equal(3))
+
+		Expect(recorder.Cassette().Interactions).To(HaveLen(3))
+		Expect(recorder.Cassette().Save(path)).To(Succeed())
+
+		data, err := ioutil.ReadFile(path)
+		Expect(err).To(BeNil())
+		Expect(string(data)).ToNot(ContainSubstring("secret-"))
+		Expect(string(data)).ToNot(ContainSubstring(testServer.URL))
+
+		// Replay against another server URL, without sending requests
+		cassette, err := opentoolchainv1.LoadCassette(path)
+		Expect(err).To(BeNil())
+		player, err := opentoolchainv1.NewCassetteTransport(opentoolchainv1.CassetteModeReplayConst, cassette, nil)
+		Expect(err).To(BeNil())
+		openToolchainService = newService("http://localhost:1", player)
+
+		replayedToolchain, response, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(replayedToolchain).To(Equal(recordedToolchain))
+
+		tektonPipeline, _, err := openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(*tektonPipeline.Name).To(Equal("pipeline"))
+		Expect(*tektonPipeline.EnvProperties[0].Value).To(Equal(opentoolchainv1.RedactedValue))
+
+		Expect(player.Unplayed()).To(HaveLen(1))
+		_, response, err = openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "missing"))
+		Expect(err).ToNot(BeNil())
+		Expect(response.StatusCode).To(Equal(404))
+		Expect(player.Unplayed()).To(BeEmpty())
+		Expect(requests).To(Equal(3))
+
+		// Every interaction is replayed once
+		_, _, err = openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("no recorded interaction for GET /devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1"))
+	})
+	It(`Apply a custom scrubber`, func() {
+		recorder, err := opentoolchainv1.NewCassetteTransport(opentoolchainv1.CassetteModeRecordConst, nil, nil)
+		Expect(err).To(BeNil())
+		recorder.SetScrubber(func(interaction *opentoolchainv1.CassetteInteraction) {
+			interaction.Response.Body = strings.Replace(interaction.Response.Body, "toolchain", "scrubbed", -1)
+		})
+		openToolchainService := newService(testServer.URL, recorder)
+
+		_, _, err = openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		Expect(recorder.Cassette().Interactions[0].Response.Body).To(ContainSubstring(`"name":"scrubbed"`))
+		Expect(recorder.Cassette().Interactions[0].Request.Headers.Get("Authorization")).To(BeEmpty())
+	})
+	It(`Invoke NewCassetteTransport with error`, func() {
+		transport, err := opentoolchainv1.NewCassetteTransport("rewind", nil, nil)
+		Expect(err).ToNot(BeNil())
+		Expect(transport).To(BeNil())
+
+		_, err = opentoolchainv1.LoadCassette(filepath.Join(dir, "missing.json"))
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,205 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+const defaultRetryOnConflictMaxAttempts = 5
+
+// TektonPipelineConflictError : Returned by PatchTektonPipeline when the pipeline was updated after the version the
+// patch is based on was read
+type TektonPipelineConflictError struct {
+	// GUID of the pipeline.
+	GUID string
+
+	// UpdatedAtTimestamp of the version the patch is based on.
+	ExpectedUpdatedAtTimestamp float64
+
+	// The version of the pipeline the patch is based on, nil unless it was set with SetBase on the patch options.
+	Base *TektonPipeline
+
+	// The current version of the pipeline.
+	Current *TektonPipeline
+
+	// The rejected patch, to be applied again to Current.
+	Patch *PatchTektonPipelineOptions
+}
+
+// Error returns the error message
+func (conflictError *TektonPipelineConflictError) Error() string {
+	current := "none"
+	if conflictError.Current != nil && conflictError.Current.UpdatedAtTimestamp != nil {
+		current = fmt.Sprint(*conflictError.Current.UpdatedAtTimestamp)
+	}
+	return fmt.Sprintf("pipeline %s was updated concurrently: expected updated_at_timestamp %v, found %s",
+		conflictError.GUID, conflictError.ExpectedUpdatedAtTimestamp, current)
+}
+
+// IsTektonPipelineConflict returns true if err is or wraps a *TektonPipelineConflictError
+func IsTektonPipelineConflict(err error) bool {
+	var conflictError *TektonPipelineConflictError
+	return errors.As(err, &conflictError)
+}
+
+// checkTektonPipelineUnchanged reads the pipeline to patch and returns a *TektonPipelineConflictError if its
+// UpdatedAtTimestamp differs from the expected one.
+func (openToolchain *OpenToolchainV1) checkTektonPipelineUnchanged(ctx context.Context, patchTektonPipelineOptions *PatchTektonPipelineOptions) (response *core.DetailedResponse, err error) {
+	getTektonPipelineOptions := openToolchain.NewGetTektonPipelineOptions(*patchTektonPipelineOptions.GUID, *patchTektonPipelineOptions.Region)
+	getTektonPipelineOptions.SetHeaders(patchTektonPipelineOptions.Headers)
+	current, response, err := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+
+	expected := *patchTektonPipelineOptions.ExpectedUpdatedAtTimestamp
+	if current == nil || current.UpdatedAtTimestamp == nil || *current.UpdatedAtTimestamp != expected {
+		err = &TektonPipelineConflictError{
+			GUID:                       *patchTektonPipelineOptions.GUID,
+			ExpectedUpdatedAtTimestamp: expected,
+			Base:                       patchTektonPipelineOptions.Base,
+			Current:                    current,
+			Patch:                      patchTektonPipelineOptions,
+		}
+	}
+	return
+}
+
+// TektonPipelineMutation : Changes applied to a Tekton pipeline by RetryOnConflict
+// The mutation receives the current version of the pipeline and sets the changes on patchTektonPipelineOptions,
+// which is created for each attempt. Returning an error stops RetryOnConflict.
+type TektonPipelineMutation func(pipeline *TektonPipeline, patchTektonPipelineOptions *PatchTektonPipelineOptions) error
+
+// RetryOnConflict : Apply a mutation to a Tekton pipeline, retrying when the pipeline is updated concurrently
+// The pipeline is read, mutated and patched with its UpdatedAtTimestamp as the expected one. When the patch fails
+// with a *TektonPipelineConflictError, the mutation is applied again to the current version of the pipeline, up to
+// MaxAttempts times.
+func (openToolchain *OpenToolchainV1) RetryOnConflict(retryOnConflictOptions *RetryOnConflictOptions) (result *TektonPipeline, response *core.DetailedResponse, err error) {
+	return openToolchain.RetryOnConflictWithContext(context.Background(), retryOnConflictOptions)
+}
+
+// RetryOnConflictWithContext is an alternate form of the RetryOnConflict method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) RetryOnConflictWithContext(ctx context.Context, retryOnConflictOptions *RetryOnConflictOptions) (result *TektonPipeline, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(retryOnConflictOptions, "retryOnConflictOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(retryOnConflictOptions, "retryOnConflictOptions")
+	if err != nil {
+		return
+	}
+
+	maxAttempts := defaultRetryOnConflictMaxAttempts
+	if retryOnConflictOptions.MaxAttempts != nil && *retryOnConflictOptions.MaxAttempts > 0 {
+		maxAttempts = int(*retryOnConflictOptions.MaxAttempts)
+	}
+	guid := *retryOnConflictOptions.GUID
+	region := *retryOnConflictOptions.Region
+
+	getTektonPipelineOptions := openToolchain.NewGetTektonPipelineOptions(guid, region)
+	getTektonPipelineOptions.SetHeaders(retryOnConflictOptions.Headers)
+	pipeline, response, err := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		if pipeline == nil || pipeline.UpdatedAtTimestamp == nil {
+			err = fmt.Errorf("pipeline %s has no updated_at_timestamp", guid)
+			return
+		}
+
+		patchTektonPipelineOptions := openToolchain.NewPatchTektonPipelineOptions(guid, region)
+		patchTektonPipelineOptions.SetBase(pipeline)
+		patchTektonPipelineOptions.SetHeaders(retryOnConflictOptions.Headers)
+		err = retryOnConflictOptions.Mutate(pipeline, patchTektonPipelineOptions)
+		if err != nil {
+			return
+		}
+
+		result, response, err = openToolchain.PatchTektonPipelineWithContext(ctx, patchTektonPipelineOptions)
+		var conflictError *TektonPipelineConflictError
+		if !errors.As(err, &conflictError) {
+			return
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return
+		}
+		pipeline = conflictError.Current
+	}
+}
+
+// RetryOnConflictOptions : The RetryOnConflict options.
+type RetryOnConflictOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	Mutate TektonPipelineMutation `validate:"required"`
+
+	// Maximum number of patch attempts. Defaults to 5.
+	MaxAttempts *int64
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRetryOnConflictOptions : Instantiate RetryOnConflictOptions
+func (*OpenToolchainV1) NewRetryOnConflictOptions(guid string, region string, mutate TektonPipelineMutation) *RetryOnConflictOptions {
+	return &RetryOnConflictOptions{
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		Mutate: mutate,
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *RetryOnConflictOptions) SetGUID(guid string) *RetryOnConflictOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *RetryOnConflictOptions) SetRegion(region string) *RetryOnConflictOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetMutate : Allow user to set Mutate
+func (options *RetryOnConflictOptions) SetMutate(mutate TektonPipelineMutation) *RetryOnConflictOptions {
+	options.Mutate = mutate
+	return options
+}
+
+// SetMaxAttempts : Allow user to set MaxAttempts
+func (options *RetryOnConflictOptions) SetMaxAttempts(maxAttempts int64) *RetryOnConflictOptions {
+	options.MaxAttempts = core.Int64Ptr(maxAttempts)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RetryOnConflictOptions) SetHeaders(param map[string]string) *RetryOnConflictOptions {
+	options.Headers = param
+	return options
+}
@@ -0,0 +1,194 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Optimistic concurrency`, func() {
+	var testServer *httptest.Server
+	var timestamp float64
+	var gets, patches int
+	// Number of GET requests after which the pipeline is updated by someone else, before answering.
+	var concurrentUpdates map[int]bool
+	var patchedProperties []string
+
+	BeforeEach(func() {
+		timestamp = 1000
+		gets, patches = 0, 0
+		concurrentUpdates = map[int]bool{}
+		patchedProperties = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.URL.Path).To(ContainSubstring("/tekton-pipelines/pl1"))
+			res.Header().Set("Content-type", "application/json")
+			switch req.Method {
+			case "GET":
+				gets++
+				if concurrentUpdates[gets] {
+					timestamp++
+				}
+			case "PATCH":
+				Expect(strings.HasSuffix(req.URL.Path, "/config")).To(BeTrue())
+				patches++
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				for _, property := range body["envProperties"].([]interface{}) {
+					patchedProperties = append(patchedProperties, property.(map[string]interface{})["value"].(string))
+				}
+				timestamp++
+			}
+			res.WriteHeader(200)
+			fmt.Fprintf(res, `{"id": "pl1", "name": "pipeline", "toolchainId": "tc1", "updated_at_timestamp": %v, "envProperties": [{"name": "version", "value": "%v", "type": "TEXT"}]}`, timestamp, timestamp)
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke PatchTektonPipeline with ExpectedUpdatedAtTimestamp successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		pipeline, _, err := openToolchainService.GetTektonPipeline(openToolchainService.NewGetTektonPipelineOptions("pl1", "us-south"))
+		Expect(err).To(BeNil())
+
+		patchTektonPipelineOptionsModel := openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south")
+		patchTektonPipelineOptionsModel.SetExpectedUpdatedAtTimestamp(*pipeline.UpdatedAtTimestamp)
+		patchTektonPipelineOptionsModel.SetEnvProperties(pipeline.EnvProperties)
+		result, _, err := openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(*result.UpdatedAtTimestamp).To(Equal(float64(1001)))
+		Expect(gets).To(Equal(2))
+		Expect(patches).To(Equal(1))
+	})
+	It(`Invoke PatchTektonPipeline with error: conflict`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		concurrentUpdates[1] = true
+		patchTektonPipelineOptionsModel := openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south")
+		patchTektonPipelineOptionsModel.SetExpectedUpdatedAtTimestamp(1000)
+		patchTektonPipelineOptionsModel.SetEnvProperties([]opentoolchainv1.EnvProperty{{Name: core.StringPtr("version"), Value: core.StringPtr("patched"), Type: core.StringPtr("TEXT")}})
+		result, _, err := openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(result).To(BeNil())
+		Expect(err).ToNot(BeNil())
+		Expect(opentoolchainv1.IsTektonPipelineConflict(err)).To(BeTrue())
+		Expect(err.Error()).To(Equal("pipeline pl1 was updated concurrently: expected updated_at_timestamp 1000, found 1001"))
+
+		var conflictError *opentoolchainv1.TektonPipelineConflictError
+		Expect(errors.As(err, &conflictError)).To(BeTrue())
+		Expect(conflictError.GUID).To(Equal("pl1"))
+		Expect(conflictError.ExpectedUpdatedAtTimestamp).To(Equal(float64(1000)))
+		Expect(*conflictError.Current.UpdatedAtTimestamp).To(Equal(float64(1001)))
+		Expect(conflictError.Base).To(BeNil())
+		Expect(conflictError.Patch).To(BeIdenticalTo(patchTektonPipelineOptionsModel))
+		Expect(*conflictError.Patch.EnvProperties[0].Value).To(Equal("patched"))
+		Expect(patches).To(Equal(0))
+
+		// The version set with SetBase is returned as the base of the conflict
+		base := &opentoolchainv1.TektonPipeline{ID: core.StringPtr("pl1"), UpdatedAtTimestamp: core.Float64Ptr(1000)}
+		patchTektonPipelineOptionsModel = openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south").SetBase(base)
+		Expect(*patchTektonPipelineOptionsModel.ExpectedUpdatedAtTimestamp).To(Equal(float64(1000)))
+		_, _, err = openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(errors.As(err, &conflictError)).To(BeTrue())
+		Expect(conflictError.Base).To(BeIdenticalTo(base))
+		Expect(patches).To(Equal(0))
+
+		Expect(opentoolchainv1.IsTektonPipelineConflict(errors.New("other"))).To(BeFalse())
+	})
+	It(`Invoke RetryOnConflict successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		// The pipeline is updated by someone else between the first read and the patch
+		concurrentUpdates[2] = true
+		mutations := 0
+		mutate := func(pipeline *opentoolchainv1.TektonPipeline, patchTektonPipelineOptions *opentoolchainv1.PatchTektonPipelineOptions) error {
+			mutations++
+			envProperties := append([]opentoolchainv1.EnvProperty{}, pipeline.EnvProperties...)
+			envProperties[0].Value = core.StringPtr(*envProperties[0].Value + "-patched")
+			patchTektonPipelineOptions.SetEnvProperties(envProperties)
+			return nil
+		}
+		result, response, err := openToolchainService.RetryOnConflict(openToolchainService.NewRetryOnConflictOptions("pl1", "us-south", mutate))
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(mutations).To(Equal(2))
+		Expect(patches).To(Equal(1))
+		Expect(patchedProperties).To(Equal([]string{"1001-patched"}))
+		Expect(*result.UpdatedAtTimestamp).To(Equal(float64(1002)))
+	})
+	It(`Invoke RetryOnConflict with error: too many conflicts`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		concurrentUpdates[2] = true
+		concurrentUpdates[3] = true
+		mutate := func(pipeline *opentoolchainv1.TektonPipeline, patchTektonPipelineOptions *opentoolchainv1.PatchTektonPipelineOptions) error {
+			patchTektonPipelineOptions.SetEnvProperties([]opentoolchainv1.EnvProperty{{Name: core.StringPtr("version"), Value: core.StringPtr("patched"), Type: core.StringPtr("TEXT")}})
+			return nil
+		}
+		retryOnConflictOptionsModel := openToolchainService.NewRetryOnConflictOptions("pl1", "us-south", mutate)
+		retryOnConflictOptionsModel.SetMaxAttempts(2)
+		_, _, err := openToolchainService.RetryOnConflict(retryOnConflictOptionsModel)
+		Expect(opentoolchainv1.IsTektonPipelineConflict(err)).To(BeTrue())
+		Expect(patches).To(Equal(0))
+
+		// The conflict exposes the version the last attempt was based on, the current version and the rejected patch
+		var conflictError *opentoolchainv1.TektonPipelineConflictError
+		Expect(errors.As(err, &conflictError)).To(BeTrue())
+		Expect(*conflictError.Base.UpdatedAtTimestamp).To(Equal(float64(1001)))
+		Expect(*conflictError.Current.UpdatedAtTimestamp).To(Equal(float64(1002)))
+		Expect(*conflictError.Patch.ExpectedUpdatedAtTimestamp).To(Equal(float64(1001)))
+		Expect(*conflictError.Patch.EnvProperties[0].Value).To(Equal("patched"))
+
+		// Errors returned by the mutation stop the retries
+		mutate = func(pipeline *opentoolchainv1.TektonPipeline, patchTektonPipelineOptions *opentoolchainv1.PatchTektonPipelineOptions) error {
+			return errors.New("mutation failed")
+		}
+		_, _, err = openToolchainService.RetryOnConflict(openToolchainService.NewRetryOnConflictOptions("pl1", "us-south", mutate))
+		Expect(err).To(MatchError("mutation failed"))
+
+		_, _, err = openToolchainService.RetryOnConflict(openToolchainService.NewRetryOnConflictOptions("pl1", "us-south", nil))
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,201 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constants associated with the CRN.ServiceName and CRN.ResourceType properties.
+const (
+	CRNServiceNameToolchainConst = "toolchain"
+	CRNResourceTypeToolConst     = "tool"
+)
+
+// crnAccountScopePrefix prefixes the account ID in the scope of a CRN.
+const crnAccountScopePrefix = "a/"
+
+// CRN : Cloud Resource Name identifying an IBM Cloud resource
+// crn:<version>:<cname>:<ctype>:<service-name>:<location>:<scope>:<service-instance>:<resource-type>:<resource>
+// Toolchains are identified by crn:v1:bluemix:public:toolchain:<region>:a/<account ID>:<toolchain GUID>:: and their
+// tool integrations by the same CRN with the tool resource type and the service instance ID as resource.
+type CRN struct {
+	Version string
+
+	CName string
+
+	CType string
+
+	ServiceName string
+
+	Location string
+
+	Scope string
+
+	ServiceInstance string
+
+	ResourceType string
+
+	Resource string
+}
+
+// ParseCRN parses a CRN
+// The resource segment may contain colons.
+func ParseCRN(crn string) (*CRN, error) {
+	segments := strings.SplitN(crn, ":", 10)
+	if len(segments) != 10 || segments[0] != "crn" {
+		return nil, fmt.Errorf("invalid CRN %q", crn)
+	}
+	return &CRN{
+		Version:         segments[1],
+		CName:           segments[2],
+		CType:           segments[3],
+		ServiceName:     segments[4],
+		Location:        segments[5],
+		Scope:           segments[6],
+		ServiceInstance: segments[7],
+		ResourceType:    segments[8],
+		Resource:        segments[9],
+	}, nil
+}
+
+// String returns the CRN
+func (crn *CRN) String() string {
+	return strings.Join([]string{
+		"crn",
+		crn.Version,
+		crn.CName,
+		crn.CType,
+		crn.ServiceName,
+		crn.Location,
+		crn.Scope,
+		crn.ServiceInstance,
+		crn.ResourceType,
+		crn.Resource,
+	}, ":")
+}
+
+// AccountID returns the account ID of the scope, or an empty string if the scope is not an account
+func (crn *CRN) AccountID() string {
+	if !strings.HasPrefix(crn.Scope, crnAccountScopePrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(crn.Scope, crnAccountScopePrefix)
+}
+
+// Region returns the location of the resource, the region of toolchains
+func (crn *CRN) Region() string {
+	return crn.Location
+}
+
+// IsToolchain returns true if the CRN identifies a toolchain
+func (crn *CRN) IsToolchain() bool {
+	return crn.ServiceName == CRNServiceNameToolchainConst && crn.ServiceInstance != "" && crn.ResourceType == "" && crn.Resource == ""
+}
+
+// IsToolIntegration returns true if the CRN identifies a tool integration of a toolchain
+func (crn *CRN) IsToolIntegration() bool {
+	return crn.ServiceName == CRNServiceNameToolchainConst && crn.ServiceInstance != "" && crn.ResourceType == CRNResourceTypeToolConst
+}
+
+// ToolchainGUID returns the GUID of the toolchain identified by the CRN or owning the tool integration it identifies,
+// or an empty string if the CRN does not belong to a toolchain
+func (crn *CRN) ToolchainGUID() string {
+	if crn.ServiceName != CRNServiceNameToolchainConst {
+		return ""
+	}
+	return crn.ServiceInstance
+}
+
+// ToolIntegration returns the CRN of the tool integration of the toolchain identified by the CRN
+func (crn *CRN) ToolIntegration(instanceID string) (*CRN, error) {
+	if !crn.IsToolchain() {
+		return nil, fmt.Errorf("invalid toolchain CRN %q", crn.String())
+	}
+	toolCRN := *crn
+	toolCRN.ResourceType = CRNResourceTypeToolConst
+	toolCRN.Resource = instanceID
+	return &toolCRN, nil
+}
+
+// parseToolchainCRN parses the CRN of a toolchain.
+func parseToolchainCRN(crn string) (*CRN, error) {
+	parsed, err := ParseCRN(crn)
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.IsToolchain() {
+		return nil, fmt.Errorf("invalid toolchain CRN %q", crn)
+	}
+	return parsed, nil
+}
+
+// ParseCRN parses the CRN of the toolchain
+func (toolchain *Toolchain) ParseCRN() (*CRN, error) {
+	return parseToolchainCRN(stringValue(toolchain.CRN))
+}
+
+// Region returns the region of the toolchain, derived from its CRN
+func (toolchain *Toolchain) Region() (string, error) {
+	crn, err := toolchain.ParseCRN()
+	if err != nil {
+		return "", err
+	}
+	return crn.Region(), nil
+}
+
+// AccountID returns the ID of the account owning the toolchain, derived from its CRN
+func (toolchain *Toolchain) AccountID() (string, error) {
+	crn, err := toolchain.ParseCRN()
+	if err != nil {
+		return "", err
+	}
+	return crn.AccountID(), nil
+}
+
+// ParseToolchainCRN parses the CRN of the toolchain of the pipeline
+func (tektonPipeline *TektonPipeline) ParseToolchainCRN() (*CRN, error) {
+	return parseToolchainCRN(stringValue(tektonPipeline.ToolchainCRN))
+}
+
+// NewGetToolchainOptionsFromCRN : Instantiate GetToolchainOptions for the toolchain identified by a CRN
+func (openToolchain *OpenToolchainV1) NewGetToolchainOptionsFromCRN(crn string) (*GetToolchainOptions, error) {
+	parsed, err := parseToolchainCRN(crn)
+	if err != nil {
+		return nil, err
+	}
+	return openToolchain.NewGetToolchainOptions(parsed.Region(), parsed.ToolchainGUID()), nil
+}
+
+// NewPatchToolchainOptionsFromCRN : Instantiate PatchToolchainOptions for the toolchain identified by a CRN
+func (openToolchain *OpenToolchainV1) NewPatchToolchainOptionsFromCRN(crn string) (*PatchToolchainOptions, error) {
+	parsed, err := parseToolchainCRN(crn)
+	if err != nil {
+		return nil, err
+	}
+	return openToolchain.NewPatchToolchainOptions(parsed.Region(), parsed.ToolchainGUID()), nil
+}
+
+// NewDeleteToolchainOptionsFromCRN : Instantiate DeleteToolchainOptions for the toolchain identified by a CRN
+func (openToolchain *OpenToolchainV1) NewDeleteToolchainOptionsFromCRN(crn string) (*DeleteToolchainOptions, error) {
+	parsed, err := parseToolchainCRN(crn)
+	if err != nil {
+		return nil, err
+	}
+	return openToolchain.NewDeleteToolchainOptions(parsed.Region(), parsed.ToolchainGUID()), nil
+}
@@ -0,0 +1,124 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`CRN`, func() {
+	const toolchainCRN = "crn:v1:bluemix:public:toolchain:eu-de:a/acct1:tc1::"
+
+	It(`Invoke ParseCRN successfully`, func() {
+		crn, err := opentoolchainv1.ParseCRN(toolchainCRN)
+		Expect(err).To(BeNil())
+		Expect(*crn).To(Equal(opentoolchainv1.CRN{
+			Version:         "v1",
+			CName:           "bluemix",
+			CType:           "public",
+			ServiceName:     "toolchain",
+			Location:        "eu-de",
+			Scope:           "a/acct1",
+			ServiceInstance: "tc1",
+		}))
+		Expect(crn.String()).To(Equal(toolchainCRN))
+		Expect(crn.Region()).To(Equal("eu-de"))
+		Expect(crn.AccountID()).To(Equal("acct1"))
+		Expect(crn.ToolchainGUID()).To(Equal("tc1"))
+		Expect(crn.IsToolchain()).To(BeTrue())
+		Expect(crn.IsToolIntegration()).To(BeFalse())
+
+		toolCRN, err := crn.ToolIntegration("repo1")
+		Expect(err).To(BeNil())
+		Expect(toolCRN.String()).To(Equal("crn:v1:bluemix:public:toolchain:eu-de:a/acct1:tc1:tool:repo1"))
+		Expect(toolCRN.IsToolIntegration()).To(BeTrue())
+		Expect(toolCRN.ToolchainGUID()).To(Equal("tc1"))
+		_, err = toolCRN.ToolIntegration("repo2")
+		Expect(err).ToNot(BeNil())
+
+		// The resource may contain colons
+		crn, err = opentoolchainv1.ParseCRN("crn:v1:bluemix:public:cloud-object-storage:global:o/org1:cos1:object:bucket:key")
+		Expect(err).To(BeNil())
+		Expect(crn.Resource).To(Equal("bucket:key"))
+		Expect(crn.AccountID()).To(BeEmpty())
+		Expect(crn.ToolchainGUID()).To(BeEmpty())
+		Expect(crn.String()).To(Equal("crn:v1:bluemix:public:cloud-object-storage:global:o/org1:cos1:object:bucket:key"))
+	})
+	It(`Invoke ParseCRN with error`, func() {
+		for _, crn := range []string{"", "tc1", "crn:v1:bluemix:public:toolchain:eu-de", "urn:v1:bluemix:public:toolchain:eu-de:a/acct1:tc1::"} {
+			_, err := opentoolchainv1.ParseCRN(crn)
+			Expect(err).ToNot(BeNil())
+		}
+	})
+	It(`Invoke CRN helpers of models successfully`, func() {
+		toolchain := &opentoolchainv1.Toolchain{CRN: core.StringPtr(toolchainCRN)}
+		region, err := toolchain.Region()
+		Expect(err).To(BeNil())
+		Expect(region).To(Equal("eu-de"))
+		accountID, err := toolchain.AccountID()
+		Expect(err).To(BeNil())
+		Expect(accountID).To(Equal("acct1"))
+
+		pipeline := &opentoolchainv1.TektonPipeline{ToolchainCRN: core.StringPtr(toolchainCRN)}
+		crn, err := pipeline.ParseToolchainCRN()
+		Expect(err).To(BeNil())
+		Expect(crn.ToolchainGUID()).To(Equal("tc1"))
+
+		_, err = (&opentoolchainv1.Toolchain{}).Region()
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Invoke GetToolchain with a CRN successfully`, func() {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.URL.Path).To(Equal("/devops-api.eu-de.devops.cloud.ibm.com/v1/toolchains/tc1"))
+			res.Header().Set("Content-type", "application/json")
+			res.WriteHeader(200)
+			fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain"}]}`)
+		}))
+		defer testServer.Close()
+
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		getToolchainOptionsModel, err := openToolchainService.NewGetToolchainOptionsFromCRN(toolchainCRN)
+		Expect(err).To(BeNil())
+		result, _, err := openToolchainService.GetToolchain(getToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(*result.Items[0].ToolchainGUID).To(Equal("tc1"))
+
+		patchToolchainOptionsModel, err := openToolchainService.NewPatchToolchainOptionsFromCRN(toolchainCRN)
+		Expect(err).To(BeNil())
+		Expect(*patchToolchainOptionsModel.GUID).To(Equal("tc1"))
+		deleteToolchainOptionsModel, err := openToolchainService.NewDeleteToolchainOptionsFromCRN(toolchainCRN)
+		Expect(err).To(BeNil())
+		Expect(*deleteToolchainOptionsModel.Region).To(Equal("eu-de"))
+
+		_, err = openToolchainService.NewGetToolchainOptionsFromCRN("crn:v1:bluemix:public:toolchain:eu-de:a/acct1:tc1:tool:repo1")
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,379 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// ClientTokenMarker returns the marker identifying the resources created with a client token, appended to their name
+// or used as one of their tags.
+func ClientTokenMarker(clientToken string) string {
+	return "[client-token:" + clientToken + "]"
+}
+
+// ClientTokenName returns name carrying the client token marker
+func ClientTokenName(name string, clientToken string) string {
+	marker := ClientTokenMarker(clientToken)
+	if name == "" {
+		return marker
+	}
+	if strings.HasSuffix(name, marker) {
+		return name
+	}
+	return name + " " + marker
+}
+
+// HasClientToken returns true if the name or one of the tags carries the client token marker
+func HasClientToken(name string, tags []string, clientToken string) bool {
+	marker := ClientTokenMarker(clientToken)
+	if strings.Contains(name, marker) {
+		return true
+	}
+	for _, tag := range tags {
+		if tag == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// IdempotentCreateResult : The outcome of an idempotent create operation
+type IdempotentCreateResult struct {
+	// GUID of the toolchain or instance ID of the tool integration, empty if the tool integration was created but
+	// could not be found afterwards.
+	ID string
+
+	// Whether the resource was created by this call, false if it was created by a previous attempt.
+	Created bool
+
+	// The toolchain carrying the client token, for CreateToolchainIdempotent.
+	Toolchain *Toolchain
+
+	// The tool integration carrying the client token, for CreateServiceInstanceIdempotent.
+	Service *Service
+}
+
+// CreateServiceInstanceIdempotent : Create a tool integration at most once for a client token
+// The client token is recorded in the name parameter of the tool integration. Before creating it, the services of the
+// toolchain are looked up with GetToolchain and the tool integration carrying the client token, created by a previous
+// attempt, is returned instead of creating a duplicate. Retrying with the same client token after a timeout is
+// therefore safe.
+func (openToolchain *OpenToolchainV1) CreateServiceInstanceIdempotent(createServiceInstanceIdempotentOptions *CreateServiceInstanceIdempotentOptions) (result *IdempotentCreateResult, response *core.DetailedResponse, err error) {
+	return openToolchain.CreateServiceInstanceIdempotentWithContext(context.Background(), createServiceInstanceIdempotentOptions)
+}
+
+// CreateServiceInstanceIdempotentWithContext is an alternate form of the CreateServiceInstanceIdempotent method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) CreateServiceInstanceIdempotentWithContext(ctx context.Context, createServiceInstanceIdempotentOptions *CreateServiceInstanceIdempotentOptions) (result *IdempotentCreateResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(createServiceInstanceIdempotentOptions, "createServiceInstanceIdempotentOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(createServiceInstanceIdempotentOptions, "createServiceInstanceIdempotentOptions")
+	if err != nil {
+		return
+	}
+	createServiceInstanceOptions := *createServiceInstanceIdempotentOptions.CreateServiceInstanceOptions
+	if createServiceInstanceOptions.ToolchainID == nil || *createServiceInstanceOptions.ToolchainID == "" {
+		err = fmt.Errorf("createServiceInstanceIdempotentOptions.CreateServiceInstanceOptions.ToolchainID is required")
+		return
+	}
+	region := *createServiceInstanceIdempotentOptions.Region
+	toolchainID := *createServiceInstanceOptions.ToolchainID
+	clientToken := *createServiceInstanceIdempotentOptions.ClientToken
+
+	service, response, err := openToolchain.findServiceWithClientToken(ctx, region, toolchainID, clientToken, createServiceInstanceIdempotentOptions.Headers)
+	if err != nil {
+		return
+	}
+	if service != nil {
+		result = &IdempotentCreateResult{
+			ID:      stringValue(service.InstanceID),
+			Service: service,
+		}
+		return
+	}
+
+	parameters := CreateServiceInstanceParamsParameters{}
+	if createServiceInstanceOptions.Parameters != nil {
+		parameters = *createServiceInstanceOptions.Parameters
+	}
+	parameters.Name = core.StringPtr(ClientTokenName(stringValue(parameters.Name), clientToken))
+	createServiceInstanceOptions.Parameters = &parameters
+
+	_, response, err = openToolchain.CreateServiceInstanceWithContext(ctx, &createServiceInstanceOptions)
+	if err != nil {
+		return
+	}
+	result = &IdempotentCreateResult{Created: true}
+
+	// The create response does not identify the tool integration, look it up
+	service, _, lookupErr := openToolchain.findServiceWithClientToken(ctx, region, toolchainID, clientToken, createServiceInstanceIdempotentOptions.Headers)
+	if lookupErr == nil && service != nil {
+		result.ID = stringValue(service.InstanceID)
+		result.Service = service
+	}
+	return
+}
+
+// findServiceWithClientToken returns the tool integration of the toolchain carrying the client token, if any.
+func (openToolchain *OpenToolchainV1) findServiceWithClientToken(ctx context.Context, region string, toolchainID string, clientToken string, headers map[string]string) (*Service, *core.DetailedResponse, error) {
+	getToolchainOptions := openToolchain.NewGetToolchainOptions(region, toolchainID)
+	getToolchainOptions.SetIncludes(NewToolchainIncludes().WithServices())
+	getToolchainOptions.SetHeaders(headers)
+	toolchainResponse, response, err := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+	if err != nil {
+		return nil, response, err
+	}
+	if toolchainResponse == nil || len(toolchainResponse.Items) == 0 {
+		return nil, response, fmt.Errorf("toolchain %s not found", toolchainID)
+	}
+	toolchain := &toolchainResponse.Items[0]
+	for i := range toolchain.Services {
+		service := &toolchain.Services[i]
+		name, _ := service.Parameters["name"].(string)
+		if HasClientToken(name, service.Tags, clientToken) {
+			return service, response, nil
+		}
+	}
+	return nil, response, nil
+}
+
+// CreateToolchainIdempotent : Create a toolchain at most once for a client token
+// The client token is recorded in the name of the toolchain, set with the name property of CreateToolchainOptions and
+// built with ClientTokenName. Before creating it, the toolchains of the region named so, or tagged with the client
+// token marker, are searched with the IBM Cloud Global Search API and the toolchain created by a previous attempt is
+// returned instead of creating a duplicate. Retrying with the same client token after a timeout or a lost response is
+// therefore safe, once the previous toolchain is indexed by Global Search.
+func (openToolchain *OpenToolchainV1) CreateToolchainIdempotent(createToolchainIdempotentOptions *CreateToolchainIdempotentOptions) (result *IdempotentCreateResult, response *core.DetailedResponse, err error) {
+	return openToolchain.CreateToolchainIdempotentWithContext(context.Background(), createToolchainIdempotentOptions)
+}
+
+// CreateToolchainIdempotentWithContext is an alternate form of the CreateToolchainIdempotent method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) CreateToolchainIdempotentWithContext(ctx context.Context, createToolchainIdempotentOptions *CreateToolchainIdempotentOptions) (result *IdempotentCreateResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(createToolchainIdempotentOptions, "createToolchainIdempotentOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(createToolchainIdempotentOptions, "createToolchainIdempotentOptions")
+	if err != nil {
+		return
+	}
+	region := *createToolchainIdempotentOptions.Region
+	clientToken := *createToolchainIdempotentOptions.ClientToken
+
+	// Copy the options and their properties so that the options of the caller are not modified
+	createToolchainOptions := *createToolchainIdempotentOptions.CreateToolchainOptions
+	createToolchainOptions.additionalProperties = make(map[string]interface{})
+	for key, value := range createToolchainIdempotentOptions.CreateToolchainOptions.GetProperties() {
+		createToolchainOptions.additionalProperties[key] = value
+	}
+	name, _ := createToolchainOptions.additionalProperties["name"].(string)
+	name = ClientTokenName(name, clientToken)
+	createToolchainOptions.SetProperty("name", name)
+
+	guid, toolchain, response, err := openToolchain.findToolchainWithClientToken(ctx, region, name, clientToken, createToolchainIdempotentOptions.Headers)
+	if err != nil {
+		return
+	}
+	if guid != "" {
+		result = &IdempotentCreateResult{
+			ID:        guid,
+			Toolchain: toolchain,
+		}
+		return
+	}
+
+	response, err = openToolchain.CreateToolchainWithContext(ctx, &createToolchainOptions)
+	if err != nil {
+		return
+	}
+	result = &IdempotentCreateResult{Created: true}
+	if response != nil {
+		result.ID = toolchainGUIDFromLocation(response.Headers.Get("Location"))
+	}
+	return
+}
+
+// findToolchainWithClientToken returns the GUID and the fields of the toolchain of the region carrying the client
+// token, if any. Search results that no longer exist, e.g. deleted toolchains not yet removed from the index, are
+// ignored.
+func (openToolchain *OpenToolchainV1) findToolchainWithClientToken(ctx context.Context, region string, name string, clientToken string, headers map[string]string) (string, *Toolchain, *core.DetailedResponse, error) {
+	query := fmt.Sprintf("service_name:toolchain AND type:toolchain AND region:%s AND (name:%s OR tags:%s)",
+		searchQuote(region), searchQuote(name), searchQuote(ClientTokenMarker(clientToken)))
+
+	var searchCursor *string
+	for {
+		page, response, err := openToolchain.searchToolchains(ctx, query, searchCursor, headers)
+		if err != nil {
+			return "", nil, response, err
+		}
+		if page == nil {
+			return "", nil, response, nil
+		}
+		for i := range page.Items {
+			item := &page.Items[i]
+			guid := item.GUID()
+			if guid == "" || !HasClientToken(stringValue(item.Name), item.Tags, clientToken) {
+				continue
+			}
+			getToolchainOptions := openToolchain.NewGetToolchainOptions(region, guid)
+			getToolchainOptions.SetIncludes(NewToolchainIncludes().WithFields())
+			getToolchainOptions.SetHeaders(headers)
+			toolchainResponse, getResponse, getErr := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+			if getErr != nil {
+				if getResponse != nil && getResponse.StatusCode == http.StatusNotFound {
+					continue
+				}
+				return "", nil, getResponse, getErr
+			}
+			if toolchainResponse == nil || len(toolchainResponse.Items) == 0 {
+				continue
+			}
+			return guid, &toolchainResponse.Items[0], getResponse, nil
+		}
+		if len(page.Items) < searchToolchainsPageSize || page.SearchCursor == nil || *page.SearchCursor == "" {
+			return "", nil, response, nil
+		}
+		searchCursor = page.SearchCursor
+	}
+}
+
+// toolchainGUIDFromLocation returns the GUID of the toolchain found in the URL of a toolchain, e.g.
+// https://cloud.ibm.com/devops/toolchains/<guid>?env_id=ibm:yp:us-south
+func toolchainGUIDFromLocation(location string) string {
+	locationURL, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(locationURL.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "toolchains" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// CreateServiceInstanceIdempotentOptions : The CreateServiceInstanceIdempotent options.
+type CreateServiceInstanceIdempotentOptions struct {
+	// Options of the tool integration to create, ToolchainID is required.
+	CreateServiceInstanceOptions *CreateServiceInstanceOptions `validate:"required"`
+
+	// Region of the toolchain.
+	Region *string `validate:"required,ne="`
+
+	// Token identifying the tool integration across attempts.
+	ClientToken *string `validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCreateServiceInstanceIdempotentOptions : Instantiate CreateServiceInstanceIdempotentOptions
+func (*OpenToolchainV1) NewCreateServiceInstanceIdempotentOptions(createServiceInstanceOptions *CreateServiceInstanceOptions, region string, clientToken string) *CreateServiceInstanceIdempotentOptions {
+	return &CreateServiceInstanceIdempotentOptions{
+		CreateServiceInstanceOptions: createServiceInstanceOptions,
+		Region:                       core.StringPtr(region),
+		ClientToken:                  core.StringPtr(clientToken),
+	}
+}
+
+// SetCreateServiceInstanceOptions : Allow user to set CreateServiceInstanceOptions
+func (options *CreateServiceInstanceIdempotentOptions) SetCreateServiceInstanceOptions(createServiceInstanceOptions *CreateServiceInstanceOptions) *CreateServiceInstanceIdempotentOptions {
+	options.CreateServiceInstanceOptions = createServiceInstanceOptions
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *CreateServiceInstanceIdempotentOptions) SetRegion(region string) *CreateServiceInstanceIdempotentOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetClientToken : Allow user to set ClientToken
+func (options *CreateServiceInstanceIdempotentOptions) SetClientToken(clientToken string) *CreateServiceInstanceIdempotentOptions {
+	options.ClientToken = core.StringPtr(clientToken)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CreateServiceInstanceIdempotentOptions) SetHeaders(param map[string]string) *CreateServiceInstanceIdempotentOptions {
+	options.Headers = param
+	return options
+}
+
+// CreateToolchainIdempotentOptions : The CreateToolchainIdempotent options.
+type CreateToolchainIdempotentOptions struct {
+	// Options of the toolchain to create, the name property is suffixed with the client token marker.
+	CreateToolchainOptions *CreateToolchainOptions `validate:"required"`
+
+	// Region of the toolchain.
+	Region *string `validate:"required,ne="`
+
+	// Token identifying the toolchain across attempts.
+	ClientToken *string `validate:"required,ne="`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCreateToolchainIdempotentOptions : Instantiate CreateToolchainIdempotentOptions
+func (*OpenToolchainV1) NewCreateToolchainIdempotentOptions(createToolchainOptions *CreateToolchainOptions, region string, clientToken string) *CreateToolchainIdempotentOptions {
+	return &CreateToolchainIdempotentOptions{
+		CreateToolchainOptions: createToolchainOptions,
+		Region:                 core.StringPtr(region),
+		ClientToken:            core.StringPtr(clientToken),
+	}
+}
+
+// SetCreateToolchainOptions : Allow user to set CreateToolchainOptions
+func (options *CreateToolchainIdempotentOptions) SetCreateToolchainOptions(createToolchainOptions *CreateToolchainOptions) *CreateToolchainIdempotentOptions {
+	options.CreateToolchainOptions = createToolchainOptions
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *CreateToolchainIdempotentOptions) SetRegion(region string) *CreateToolchainIdempotentOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetClientToken : Allow user to set ClientToken
+func (options *CreateToolchainIdempotentOptions) SetClientToken(clientToken string) *CreateToolchainIdempotentOptions {
+	options.ClientToken = core.StringPtr(clientToken)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CreateToolchainIdempotentOptions) SetHeaders(param map[string]string) *CreateToolchainIdempotentOptions {
+	options.Headers = param
+	return options
+}
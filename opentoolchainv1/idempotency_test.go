@@ -0,0 +1,212 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Idempotent create operations`, func() {
+	var testServer *httptest.Server
+	var serviceName string
+	var toolchainCreated bool
+	var creates int
+	var searchQueries []string
+
+	BeforeEach(func() {
+		serviceName = ""
+		toolchainCreated = false
+		creates = 0
+		searchQueries = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/service_instances"):
+				creates++
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				Expect(body["toolchainId"]).To(Equal("tc1"))
+				serviceName = body["parameters"].(map[string]interface{})["name"].(string)
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"status": "ok"}`)
+			case req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/setup/deploy"):
+				creates++
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("name")).To(Equal("my toolchain [client-token:abc]"))
+				toolchainCreated = true
+				res.Header().Set("Location", "https://cloud.ibm.com/devops/toolchains/tc-new?env_id=ibm:yp:us-south")
+				res.WriteHeader(201)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+				Expect(req.URL.Query().Get("include")).To(Equal("services"))
+				services := `{"service_id": "slack", "instance_id": "slack1", "parameters": {"name": "chat"}}`
+				if serviceName != "" {
+					services += fmt.Sprintf(`, {"service_id": "githubconsolidated", "instance_id": "repo-new", "parameters": {"name": "%s"}}`, serviceName)
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain", "services": [%s]}]}`, services)
+			case req.Method == "POST" && req.URL.Path == "/api.global-search-tagging.cloud.ibm.com/v3/resources/search":
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				searchQueries = append(searchQueries, body["query"].(string))
+				items := []string{
+					`{"crn": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc-deleted::", "name": "my toolchain [client-token:abc]", "region": "us-south"}`,
+					`{"crn": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc-other::", "name": "other", "region": "us-south", "tags": ["[client-token:xyz]"]}`,
+				}
+				if toolchainCreated {
+					items = append(items, `{"crn": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc-new::", "name": "my toolchain [client-token:abc]", "region": "us-south"}`)
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"limit": 100, "items": [%s]}`, strings.Join(items, ","))
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc-new":
+				Expect(req.URL.Query().Get("include")).To(Equal("fields"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc-new", "name": "my toolchain [client-token:abc]"}]}`)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc-other":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc-other", "name": "other", "tags": ["[client-token:xyz]"]}]}`)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc-deleted":
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke ClientTokenName and HasClientToken successfully`, func() {
+		Expect(opentoolchainv1.ClientTokenName("repo", "abc")).To(Equal("repo [client-token:abc]"))
+		Expect(opentoolchainv1.ClientTokenName("repo [client-token:abc]", "abc")).To(Equal("repo [client-token:abc]"))
+		Expect(opentoolchainv1.ClientTokenName("", "abc")).To(Equal("[client-token:abc]"))
+		Expect(opentoolchainv1.HasClientToken("repo [client-token:abc]", nil, "abc")).To(BeTrue())
+		Expect(opentoolchainv1.HasClientToken("repo", []string{"[client-token:abc]"}, "abc")).To(BeTrue())
+		Expect(opentoolchainv1.HasClientToken("repo [client-token:abcd]", nil, "abc")).To(BeFalse())
+	})
+	It(`Invoke CreateServiceInstanceIdempotent successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		createServiceInstanceOptionsModel := openToolchainService.NewCreateServiceInstanceOptions("ibm:yp:us-south")
+		createServiceInstanceOptionsModel.SetToolchainID("tc1")
+		createServiceInstanceOptionsModel.SetServiceID("githubconsolidated")
+		createServiceInstanceOptionsModel.SetParameters(&opentoolchainv1.CreateServiceInstanceParamsParameters{
+			Name: core.StringPtr("repo"),
+		})
+		options := openToolchainService.NewCreateServiceInstanceIdempotentOptions(createServiceInstanceOptionsModel, "us-south", "abc")
+
+		result, response, err := openToolchainService.CreateServiceInstanceIdempotent(options)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(result.Created).To(BeTrue())
+		Expect(result.ID).To(Equal("repo-new"))
+		Expect(serviceName).To(Equal("repo [client-token:abc]"))
+		Expect(creates).To(Equal(1))
+
+		// The options of the caller are not modified
+		Expect(*createServiceInstanceOptionsModel.Parameters.Name).To(Equal("repo"))
+
+		// Retrying finds the tool integration created by the first attempt
+		result, _, err = openToolchainService.CreateServiceInstanceIdempotent(options)
+		Expect(err).To(BeNil())
+		Expect(result.Created).To(BeFalse())
+		Expect(result.ID).To(Equal("repo-new"))
+		Expect(*result.Service.ServiceID).To(Equal("githubconsolidated"))
+		Expect(creates).To(Equal(1))
+	})
+	It(`Invoke CreateToolchainIdempotent successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		createToolchainOptionsModel := openToolchainService.NewCreateToolchainOptions("ibm:yp:us-south", "https://github.com/open-toolchain/simple-toolchain")
+		createToolchainOptionsModel.SetAutocreate(true)
+		createToolchainOptionsModel.SetProperty("name", "my toolchain")
+		options := openToolchainService.NewCreateToolchainIdempotentOptions(createToolchainOptionsModel, "us-south", "abc")
+
+		result, response, err := openToolchainService.CreateToolchainIdempotent(options)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(201))
+		Expect(result.Created).To(BeTrue())
+		Expect(result.ID).To(Equal("tc-new"))
+		Expect(creates).To(Equal(1))
+		Expect(searchQueries).To(Equal([]string{
+			`service_name:toolchain AND type:toolchain AND region:"us-south" AND (name:"my toolchain [client-token:abc]" OR tags:"[client-token:abc]")`,
+		}))
+
+		// The options of the caller are not modified
+		Expect(createToolchainOptionsModel.GetProperties()).To(Equal(map[string]interface{}{"name": "my toolchain"}))
+
+		// Retrying after a lost response finds the toolchain created by the first attempt
+		result, _, err = openToolchainService.CreateToolchainIdempotent(options)
+		Expect(err).To(BeNil())
+		Expect(result.Created).To(BeFalse())
+		Expect(result.ID).To(Equal("tc-new"))
+		Expect(*result.Toolchain.Name).To(Equal("my toolchain [client-token:abc]"))
+		Expect(creates).To(Equal(1))
+
+		// Toolchains are also matched by tag
+		options = openToolchainService.NewCreateToolchainIdempotentOptions(createToolchainOptionsModel, "us-south", "xyz")
+		result, _, err = openToolchainService.CreateToolchainIdempotent(options)
+		Expect(err).To(BeNil())
+		Expect(result.Created).To(BeFalse())
+		Expect(result.ID).To(Equal("tc-other"))
+		Expect(creates).To(Equal(1))
+	})
+	It(`Invoke idempotent create operations with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, response, err := openToolchainService.CreateServiceInstanceIdempotent(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+
+		createServiceInstanceOptionsModel := openToolchainService.NewCreateServiceInstanceOptions("ibm:yp:us-south")
+		result, response, err = openToolchainService.CreateServiceInstanceIdempotent(openToolchainService.NewCreateServiceInstanceIdempotentOptions(createServiceInstanceOptionsModel, "us-south", "abc"))
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+
+		createToolchainOptionsModel := openToolchainService.NewCreateToolchainOptions("ibm:yp:us-south", "https://github.com/open-toolchain/simple-toolchain")
+		result, response, err = openToolchainService.CreateToolchainIdempotent(openToolchainService.NewCreateToolchainIdempotentOptions(createToolchainOptionsModel, "us-south", ""))
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+		Expect(creates).To(Equal(0))
+	})
+})
@@ -0,0 +1,184 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Types of EnvProperty
+const (
+	EnvPropertyTypeTextConst   = "TEXT"
+	EnvPropertyTypeSecureConst = "SECURE"
+)
+
+// RedactedValue replaces the secrets written to the logs.
+const RedactedValue = "[REDACTED]"
+
+// secretFields are the request and response fields whose value is never logged: the api_key and api_token
+// parameters of CreateServiceInstance and the repository_token of CreateToolchain.
+var secretFields = map[string]bool{
+	"api_key":          true,
+	"api_token":        true,
+	"repository_token": true,
+}
+
+// Logger : Structured logger receiving the requests and responses of the service operations
+// The methods take a message followed by alternating keys and values, like those of log/slog, so a *slog.Logger can
+// be used as a Logger.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// SetLogger sets the logger receiving the requests and responses of every operation, nil disables logging
+func (openToolchain *OpenToolchainV1) SetLogger(logger Logger) {
+	openToolchain.logger = logger
+}
+
+// GetLogger returns the logger receiving the requests and responses of every operation
+func (openToolchain *OpenToolchainV1) GetLogger() Logger {
+	return openToolchain.logger
+}
+
+// loggingMiddleware logs the request sent for every operation at debug level, and its response at debug level or
+// error level when the operation fails. Secrets are redacted from URLs and bodies.
+func (openToolchain *OpenToolchainV1) loggingMiddleware(next RoundTripper) RoundTripper {
+	logger := openToolchain.logger
+	return RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+		ctx := request.Context()
+		args := []interface{}{"operation", operationID, "method", request.Method, "url", redactURL(request.URL)}
+		if body, ok := readRequestBody(request); ok {
+			args = append(args, "body", redactBody(request.Header.Get("Content-Type"), body))
+		}
+		logger.DebugContext(ctx, "sending request", args...)
+
+		start := time.Now()
+		response, err := next.RoundTrip(operationID, request, result)
+
+		args = []interface{}{"operation", operationID, "duration", time.Since(start)}
+		if response != nil {
+			args = append(args, "status", response.StatusCode)
+			if response.Result != nil {
+				if body, marshalErr := json.Marshal(response.Result); marshalErr == nil {
+					args = append(args, "body", redactBody("application/json", body))
+				}
+			}
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "operation failed", append(args, "error", err.Error())...)
+		} else {
+			logger.DebugContext(ctx, "received response", args...)
+		}
+		return response, err
+	})
+}
+
+// readRequestBody returns the body of a request and replaces it with an identical reader. Compressed bodies are
+// not read.
+func readRequestBody(request *http.Request) ([]byte, bool) {
+	if request.Body == nil || request.Body == http.NoBody || request.Header.Get("Content-Encoding") != "" {
+		return nil, false
+	}
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+	request.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, true
+}
+
+// redactURL returns the URL with the secret query parameters redacted.
+func redactURL(requestURL *url.URL) string {
+	if requestURL == nil {
+		return ""
+	}
+	redacted := *requestURL
+	redacted.RawQuery = redactValues(requestURL.Query()).Encode()
+	return redacted.String()
+}
+
+// redactBody returns a JSON or form encoded body with its secrets redacted.
+func redactBody(contentType string, body []byte) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return RedactedValue
+		}
+		return redactValues(values).Encode()
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Bodies that cannot be inspected are not logged.
+		return RedactedValue
+	}
+	redacted, err := json.Marshal(redactJSON(value))
+	if err != nil {
+		return RedactedValue
+	}
+	return string(redacted)
+}
+
+func redactValues(values url.Values) url.Values {
+	for name := range values {
+		if secretFields[name] {
+			values[name] = []string{RedactedValue}
+		}
+	}
+	return values
+}
+
+// redactJSON redacts the secret fields of a decoded JSON value, along with the value of SECURE environment
+// properties.
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		secure := false
+		if propertyType, ok := v["type"].(string); ok && strings.EqualFold(propertyType, EnvPropertyTypeSecureConst) {
+			secure = true
+		}
+		for key, field := range v {
+			if secretFields[key] || (secure && key == "value") {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactJSON(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}
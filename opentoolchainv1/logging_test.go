@@ -0,0 +1,201 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+// recordingLogger keeps the records it receives.
+type recordingLogger struct {
+	mutex   sync.Mutex
+	records []logRecord
+}
+
+func (logger *recordingLogger) log(level string, msg string, args []interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	attrs := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+	logger.records = append(logger.records, logRecord{level: level, msg: msg, attrs: attrs})
+}
+
+func (logger *recordingLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	logger.log("DEBUG", msg, args)
+}
+
+func (logger *recordingLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	logger.log("INFO", msg, args)
+}
+
+func (logger *recordingLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	logger.log("WARN", msg, args)
+}
+
+func (logger *recordingLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	logger.log("ERROR", msg, args)
+}
+
+var _ = Describe(`Logger`, func() {
+	var testServer *httptest.Server
+	var logger *recordingLogger
+	var receivedBodies []string
+
+	BeforeEach(func() {
+		logger = new(recordingLogger)
+		receivedBodies = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			body, err := ioutil.ReadAll(req.Body)
+			Expect(err).To(BeNil())
+			receivedBodies = append(receivedBodies, string(body))
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/service_instances"):
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"instance_id": "si1", "parameters": {"api_token": "response-token"}}`)
+			case strings.HasSuffix(req.URL.Path, "/config"):
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl1", "name": "n", "toolchainId": "tc1", "envProperties": [{"name": "password", "value": "response-password", "type": "SECURE"}, {"name": "level", "value": "debug", "type": "TEXT"}]}`)
+			default:
+				res.WriteHeader(400)
+				fmt.Fprint(res, `{"message": "bad request"}`)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Log CreateServiceInstance without api_key and api_token`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+			Logger:        logger,
+		})
+		Expect(serviceErr).To(BeNil())
+
+		createServiceInstanceOptionsModel := openToolchainService.NewCreateServiceInstanceOptions("ibm:yp:us-south")
+		createServiceInstanceOptionsModel.SetServiceID("github")
+		createServiceInstanceOptionsModel.SetParameters(&opentoolchainv1.CreateServiceInstanceParamsParameters{
+			APIKey:   core.StringPtr("secret-key"),
+			APIToken: core.StringPtr("secret-token"),
+			Name:     core.StringPtr("repo"),
+		})
+		_, _, err := openToolchainService.CreateServiceInstance(createServiceInstanceOptionsModel)
+		Expect(err).To(BeNil())
+
+		// The service receives the secrets
+		Expect(receivedBodies).To(HaveLen(1))
+		Expect(receivedBodies[0]).To(ContainSubstring("secret-key"))
+		Expect(receivedBodies[0]).To(ContainSubstring("secret-token"))
+
+		Expect(logger.records).To(HaveLen(2))
+		Expect(logger.records[0].level).To(Equal("DEBUG"))
+		Expect(logger.records[0].msg).To(Equal("sending request"))
+		Expect(logger.records[0].attrs["operation"]).To(Equal("CreateServiceInstance"))
+		Expect(logger.records[0].attrs["method"]).To(Equal("POST"))
+		requestBody := logger.records[0].attrs["body"].(string)
+		Expect(requestBody).To(ContainSubstring(`"api_key":"[REDACTED]"`))
+		Expect(requestBody).To(ContainSubstring(`"api_token":"[REDACTED]"`))
+		Expect(requestBody).To(ContainSubstring(`"name":"repo"`))
+
+		Expect(logger.records[1].level).To(Equal("DEBUG"))
+		Expect(logger.records[1].msg).To(Equal("received response"))
+		Expect(logger.records[1].attrs["status"]).To(Equal(200))
+		Expect(logger.records[1].attrs["body"]).To(ContainSubstring(`"api_token":"[REDACTED]"`))
+		for _, record := range logger.records {
+			Expect(fmt.Sprint(record.attrs)).ToNot(ContainSubstring("secret-"))
+			Expect(fmt.Sprint(record.attrs)).ToNot(ContainSubstring("response-token"))
+		}
+	})
+	It(`Log CreateToolchain without repository_token`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		openToolchainService.SetLogger(logger)
+
+		createToolchainOptionsModel := openToolchainService.NewCreateToolchainOptions("ibm:yp:us-south", "https://github.com/org/repo")
+		createToolchainOptionsModel.SetRepositoryToken("secret-repository-token")
+		_, err := openToolchainService.CreateToolchain(createToolchainOptionsModel)
+		Expect(err).ToNot(BeNil())
+
+		Expect(receivedBodies[0]).To(ContainSubstring("secret-repository-token"))
+		Expect(logger.records).To(HaveLen(2))
+		Expect(logger.records[0].attrs["body"]).To(ContainSubstring("repository_token=%5BREDACTED%5D"))
+		Expect(logger.records[0].attrs["body"]).To(ContainSubstring("repository=https%3A%2F%2Fgithub.com%2Forg%2Frepo"))
+		Expect(logger.records[1].level).To(Equal("ERROR"))
+		Expect(logger.records[1].msg).To(Equal("operation failed"))
+		Expect(logger.records[1].attrs["status"]).To(Equal(400))
+		Expect(logger.records[1].attrs["error"]).To(Equal("bad request"))
+		for _, record := range logger.records {
+			Expect(fmt.Sprint(record.attrs)).ToNot(ContainSubstring("secret-repository-token"))
+		}
+	})
+	It(`Log PatchTektonPipeline without SECURE property values`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+			Logger:        logger,
+		})
+		Expect(serviceErr).To(BeNil())
+
+		secure, err := openToolchainService.NewEnvProperty("password", "secret-password", opentoolchainv1.EnvPropertyTypeSecureConst)
+		Expect(err).To(BeNil())
+		text, err := openToolchainService.NewEnvProperty("level", "info", opentoolchainv1.EnvPropertyTypeTextConst)
+		Expect(err).To(BeNil())
+		patchTektonPipelineOptionsModel := openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south")
+		patchTektonPipelineOptionsModel.SetEnvProperties([]opentoolchainv1.EnvProperty{*secure, *text})
+		result, _, err := openToolchainService.PatchTektonPipeline(patchTektonPipelineOptionsModel)
+		Expect(err).To(BeNil())
+
+		// The result returned to the caller is not redacted
+		Expect(*result.EnvProperties[0].Value).To(Equal("response-password"))
+
+		Expect(logger.records).To(HaveLen(2))
+		Expect(logger.records[0].attrs["body"]).To(ContainSubstring(`"value":"info"`))
+		Expect(logger.records[1].attrs["body"]).To(ContainSubstring(`"value":"debug"`))
+		for _, record := range logger.records {
+			Expect(record.attrs["body"]).To(ContainSubstring(`"value":"[REDACTED]"`))
+			Expect(fmt.Sprint(record.attrs)).ToNot(ContainSubstring("secret-password"))
+			Expect(fmt.Sprint(record.attrs)).ToNot(ContainSubstring("response-password"))
+		}
+	})
+})
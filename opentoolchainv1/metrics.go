@@ -0,0 +1,370 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Status classes of the requests reported to a MetricsCollector
+const (
+	StatusClass2xxConst   = "2xx"
+	StatusClass3xxConst   = "3xx"
+	StatusClass4xxConst   = "4xx"
+	StatusClass5xxConst   = "5xx"
+	StatusClassErrorConst = "error"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets used by Metrics.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MetricsCollector : Receives the measurements of the service operations
+// Implement this interface to feed the measurements to a metrics library, e.g. Prometheus counter and histogram
+// vectors, or use Metrics which keeps them in memory.
+type MetricsCollector interface {
+	// ObserveRequest is called once per operation with the status class of its final response, StatusClassErrorConst
+	// when no response was received, its latency including retries and the error returned to the caller, if any.
+	ObserveRequest(operationID string, statusClass string, latency time.Duration, err error)
+
+	// ObserveRetries is called when an operation needed more than one HTTP attempt.
+	ObserveRetries(operationID string, retries int)
+
+	// ObserveRateLimitWait is called when an operation was delayed by the rate limiter.
+	ObserveRateLimitWait(operationID string, wait time.Duration)
+}
+
+// SetMetricsCollector sets the collector receiving the measurements of every operation, nil disables metrics
+func (openToolchain *OpenToolchainV1) SetMetricsCollector(collector MetricsCollector) {
+	openToolchain.metrics = collector
+}
+
+// GetMetricsCollector returns the collector receiving the measurements of every operation
+func (openToolchain *OpenToolchainV1) GetMetricsCollector() MetricsCollector {
+	return openToolchain.metrics
+}
+
+// metricsMiddleware reports the measurements of every operation to the metrics collector.
+func (openToolchain *OpenToolchainV1) metricsMiddleware(next RoundTripper) RoundTripper {
+	collector := openToolchain.metrics
+	return RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+		start := time.Now()
+		response, err := next.RoundTrip(operationID, request, result)
+		latency := time.Since(start)
+
+		statusCode := 0
+		if response != nil {
+			statusCode = response.StatusCode
+		}
+		collector.ObserveRequest(operationID, statusClass(statusCode), latency, err)
+		if state := operationStateFromContext(request.Context()); state != nil {
+			if retries := state.retries(); retries > 0 {
+				collector.ObserveRetries(operationID, retries)
+			}
+			if wait := time.Duration(atomic.LoadInt64(&state.rateLimitWait)); wait > 0 {
+				collector.ObserveRateLimitWait(operationID, wait)
+			}
+		}
+		return response, err
+	})
+}
+
+// statusClass returns the status class of an HTTP status code.
+func statusClass(statusCode int) string {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return StatusClass2xxConst
+	case statusCode >= 300 && statusCode < 400:
+		return StatusClass3xxConst
+	case statusCode >= 400 && statusCode < 500:
+		return StatusClass4xxConst
+	case statusCode >= 500 && statusCode < 600:
+		return StatusClass5xxConst
+	}
+	return StatusClassErrorConst
+}
+
+// Metrics : In-memory MetricsCollector
+// Metrics is safe for concurrent use and can be shared by several service instances. Its measurements can be read
+// with Snapshot or written in the Prometheus text exposition format with WritePrometheus, which lets them be served
+// or bridged into an existing Prometheus registry without depending on a specific exporter.
+type Metrics struct {
+	mutex sync.Mutex
+
+	buckets []float64
+
+	requests map[requestMetricKey]*requestMetric
+
+	retries map[string]int64
+
+	rateLimitWaits map[string]*rateLimitWaitMetric
+}
+
+type requestMetricKey struct {
+	operationID string
+	statusClass string
+}
+
+type requestMetric struct {
+	count        int64
+	errors       int64
+	latencySum   float64
+	bucketCounts []int64
+}
+
+type rateLimitWaitMetric struct {
+	count   int64
+	seconds float64
+}
+
+// NewMetrics : Instantiate Metrics
+// buckets are the upper bounds, in seconds, of the latency histogram buckets; DefaultLatencyBuckets are used when
+// none are given.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	return &Metrics{
+		buckets:        sorted,
+		requests:       make(map[requestMetricKey]*requestMetric),
+		retries:        make(map[string]int64),
+		rateLimitWaits: make(map[string]*rateLimitWaitMetric),
+	}
+}
+
+// ObserveRequest records an operation
+func (metrics *Metrics) ObserveRequest(operationID string, statusClass string, latency time.Duration, err error) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	key := requestMetricKey{operationID: operationID, statusClass: statusClass}
+	metric, ok := metrics.requests[key]
+	if !ok {
+		metric = &requestMetric{bucketCounts: make([]int64, len(metrics.buckets))}
+		metrics.requests[key] = metric
+	}
+	metric.count++
+	if err != nil {
+		metric.errors++
+	}
+	seconds := latency.Seconds()
+	metric.latencySum += seconds
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			metric.bucketCounts[i]++
+		}
+	}
+}
+
+// ObserveRetries records the retries of an operation
+func (metrics *Metrics) ObserveRetries(operationID string, retries int) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.retries[operationID] += int64(retries)
+}
+
+// ObserveRateLimitWait records the time an operation was delayed by the rate limiter
+func (metrics *Metrics) ObserveRateLimitWait(operationID string, wait time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metric, ok := metrics.rateLimitWaits[operationID]
+	if !ok {
+		metric = new(rateLimitWaitMetric)
+		metrics.rateLimitWaits[operationID] = metric
+	}
+	metric.count++
+	metric.seconds += wait.Seconds()
+}
+
+// RequestMetrics : The measurements of the operations with the same operation ID and status class
+type RequestMetrics struct {
+	OperationID string
+
+	StatusClass string
+
+	Count int64
+
+	Errors int64
+
+	// Sum of the latencies, in seconds.
+	LatencySum float64
+
+	// Cumulative counts of the latency histogram, one per bucket of Metrics.
+	BucketCounts []int64
+}
+
+// RateLimitWaitMetrics : The rate limiter waits of the operations with the same operation ID
+type RateLimitWaitMetrics struct {
+	OperationID string
+
+	Count int64
+
+	// Total wait, in seconds.
+	Seconds float64
+}
+
+// MetricsSnapshot : A copy of the measurements of Metrics
+type MetricsSnapshot struct {
+	// Upper bounds of the latency histogram buckets, in seconds.
+	Buckets []float64
+
+	// Sorted by operation ID and status class.
+	Requests []RequestMetrics
+
+	// Retries by operation ID.
+	Retries map[string]int64
+
+	// Sorted by operation ID.
+	RateLimitWaits []RateLimitWaitMetrics
+}
+
+// Snapshot returns a copy of the current measurements
+func (metrics *Metrics) Snapshot() *MetricsSnapshot {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	snapshot := &MetricsSnapshot{
+		Buckets:        append([]float64{}, metrics.buckets...),
+		Requests:       []RequestMetrics{},
+		Retries:        make(map[string]int64, len(metrics.retries)),
+		RateLimitWaits: []RateLimitWaitMetrics{},
+	}
+	for key, metric := range metrics.requests {
+		snapshot.Requests = append(snapshot.Requests, RequestMetrics{
+			OperationID:  key.operationID,
+			StatusClass:  key.statusClass,
+			Count:        metric.count,
+			Errors:       metric.errors,
+			LatencySum:   metric.latencySum,
+			BucketCounts: append([]int64{}, metric.bucketCounts...),
+		})
+	}
+	sort.Slice(snapshot.Requests, func(i, j int) bool {
+		if snapshot.Requests[i].OperationID != snapshot.Requests[j].OperationID {
+			return snapshot.Requests[i].OperationID < snapshot.Requests[j].OperationID
+		}
+		return snapshot.Requests[i].StatusClass < snapshot.Requests[j].StatusClass
+	})
+	for operationID, retries := range metrics.retries {
+		snapshot.Retries[operationID] = retries
+	}
+	for operationID, metric := range metrics.rateLimitWaits {
+		snapshot.RateLimitWaits = append(snapshot.RateLimitWaits, RateLimitWaitMetrics{
+			OperationID: operationID,
+			Count:       metric.count,
+			Seconds:     metric.seconds,
+		})
+	}
+	sort.Slice(snapshot.RateLimitWaits, func(i, j int) bool {
+		return snapshot.RateLimitWaits[i].OperationID < snapshot.RateLimitWaits[j].OperationID
+	})
+	return snapshot
+}
+
+// WritePrometheus writes the current measurements in the Prometheus text exposition format
+func (metrics *Metrics) WritePrometheus(w io.Writer) error {
+	snapshot := metrics.Snapshot()
+	pw := &prometheusWriter{w: w}
+
+	pw.header("opentoolchain_requests_total", "Total number of operations sent to the service.", "counter")
+	for _, metric := range snapshot.Requests {
+		pw.sample("opentoolchain_requests_total", requestLabels(metric), float64(metric.Count))
+	}
+	pw.header("opentoolchain_request_errors_total", "Total number of operations that returned an error.", "counter")
+	for _, metric := range snapshot.Requests {
+		pw.sample("opentoolchain_request_errors_total", requestLabels(metric), float64(metric.Errors))
+	}
+	pw.header("opentoolchain_request_duration_seconds", "Latency of the operations, including retries.", "histogram")
+	for _, metric := range snapshot.Requests {
+		labels := requestLabels(metric)
+		for i, bound := range snapshot.Buckets {
+			pw.sample("opentoolchain_request_duration_seconds_bucket", append(labels, "le", formatPrometheusFloat(bound)), float64(metric.BucketCounts[i]))
+		}
+		pw.sample("opentoolchain_request_duration_seconds_bucket", append(labels, "le", "+Inf"), float64(metric.Count))
+		pw.sample("opentoolchain_request_duration_seconds_sum", labels, metric.LatencySum)
+		pw.sample("opentoolchain_request_duration_seconds_count", labels, float64(metric.Count))
+	}
+
+	operationIDs := make([]string, 0, len(snapshot.Retries))
+	for operationID := range snapshot.Retries {
+		operationIDs = append(operationIDs, operationID)
+	}
+	sort.Strings(operationIDs)
+	pw.header("opentoolchain_retries_total", "Total number of HTTP attempts retried.", "counter")
+	for _, operationID := range operationIDs {
+		pw.sample("opentoolchain_retries_total", []string{"operation", operationID}, float64(snapshot.Retries[operationID]))
+	}
+
+	pw.header("opentoolchain_rate_limit_waits_total", "Total number of operations delayed by the rate limiter.", "counter")
+	for _, metric := range snapshot.RateLimitWaits {
+		pw.sample("opentoolchain_rate_limit_waits_total", []string{"operation", metric.OperationID}, float64(metric.Count))
+	}
+	pw.header("opentoolchain_rate_limit_wait_seconds_total", "Total time operations were delayed by the rate limiter.", "counter")
+	for _, metric := range snapshot.RateLimitWaits {
+		pw.sample("opentoolchain_rate_limit_wait_seconds_total", []string{"operation", metric.OperationID}, metric.Seconds)
+	}
+	return pw.err
+}
+
+func requestLabels(metric RequestMetrics) []string {
+	return []string{"operation", metric.OperationID, "status_class", metric.StatusClass}
+}
+
+// prometheusWriter writes metric families in the Prometheus text exposition format, keeping the first error.
+type prometheusWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (pw *prometheusWriter) header(name string, help string, metricType string) {
+	pw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sample writes a sample, labels being given as name, value pairs.
+func (pw *prometheusWriter) sample(name string, labels []string, value float64) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", labels[i], strconv.Quote(labels[i+1])))
+	}
+	pw.printf("%s{%s} %s\n", name, strings.Join(pairs, ","), formatPrometheusFloat(value))
+}
+
+func (pw *prometheusWriter) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	_, pw.err = fmt.Fprintf(pw.w, format, args...)
+}
+
+func formatPrometheusFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
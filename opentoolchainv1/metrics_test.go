@@ -0,0 +1,143 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Metrics`, func() {
+	var testServer *httptest.Server
+	var patchRequests int
+
+	BeforeEach(func() {
+		patchRequests = 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/toolchains/missing"):
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+			case strings.HasSuffix(req.URL.Path, "/config"):
+				patchRequests++
+				if patchRequests == 1 {
+					res.Header().Set("Retry-After", "0")
+					res.WriteHeader(429)
+					fmt.Fprint(res, `{"message": "too many requests"}`)
+					return
+				}
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl1", "name": "n", "toolchainId": "tc1", "envProperties": []}`)
+			default:
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain"}]}`)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Collect operation metrics`, func() {
+		metrics := opentoolchainv1.NewMetrics(0.5, 10)
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+			Metrics:       metrics,
+			RateLimiter:   opentoolchainv1.NewRateLimiter(20, 1),
+		})
+		Expect(serviceErr).To(BeNil())
+		Expect(openToolchainService.Clone().GetMetricsCollector()).To(BeIdenticalTo(metrics))
+		openToolchainService.EnableRetries(2, 10*time.Millisecond)
+
+		for _, guid := range []string{"tc1", "tc2", "missing"} {
+			_, _, _ = openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", guid))
+		}
+		_, _, err := openToolchainService.PatchTektonPipeline(openToolchainService.NewPatchTektonPipelineOptions("pl1", "us-south"))
+		Expect(err).To(BeNil())
+
+		snapshot := metrics.Snapshot()
+		Expect(snapshot.Buckets).To(Equal([]float64{0.5, 10}))
+		Expect(snapshot.Requests).To(HaveLen(3))
+
+		Expect(snapshot.Requests[0].OperationID).To(Equal("GetToolchain"))
+		Expect(snapshot.Requests[0].StatusClass).To(Equal(opentoolchainv1.StatusClass2xxConst))
+		Expect(snapshot.Requests[0].Count).To(Equal(int64(2)))
+		Expect(snapshot.Requests[0].Errors).To(Equal(int64(0)))
+		Expect(snapshot.Requests[0].BucketCounts).To(Equal([]int64{2, 2}))
+
+		Expect(snapshot.Requests[1].OperationID).To(Equal("GetToolchain"))
+		Expect(snapshot.Requests[1].StatusClass).To(Equal(opentoolchainv1.StatusClass4xxConst))
+		Expect(snapshot.Requests[1].Count).To(Equal(int64(1)))
+		Expect(snapshot.Requests[1].Errors).To(Equal(int64(1)))
+
+		Expect(snapshot.Requests[2].OperationID).To(Equal("PatchTektonPipeline"))
+		Expect(snapshot.Requests[2].StatusClass).To(Equal(opentoolchainv1.StatusClass2xxConst))
+		Expect(snapshot.Retries).To(Equal(map[string]int64{"PatchTektonPipeline": 1}))
+
+		// The rate limiter allows a single request at once, so every operation but the first one waits
+		Expect(snapshot.RateLimitWaits).ToNot(BeEmpty())
+		for _, wait := range snapshot.RateLimitWaits {
+			Expect(wait.Count).To(BeNumerically(">", 0))
+			Expect(wait.Seconds).To(BeNumerically(">", 0))
+		}
+
+		var buffer bytes.Buffer
+		Expect(metrics.WritePrometheus(&buffer)).To(Succeed())
+		output := buffer.String()
+		Expect(output).To(ContainSubstring("# TYPE opentoolchain_requests_total counter\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_requests_total{operation="GetToolchain",status_class="2xx"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_request_errors_total{operation="GetToolchain",status_class="4xx"} 1` + "\n"))
+		Expect(output).To(ContainSubstring("# TYPE opentoolchain_request_duration_seconds histogram\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_request_duration_seconds_bucket{operation="GetToolchain",status_class="2xx",le="0.5"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_request_duration_seconds_bucket{operation="GetToolchain",status_class="2xx",le="+Inf"} 2` + "\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_request_duration_seconds_count{operation="PatchTektonPipeline",status_class="2xx"} 1` + "\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_retries_total{operation="PatchTektonPipeline"} 1` + "\n"))
+		Expect(output).To(ContainSubstring(`opentoolchain_rate_limit_waits_total{operation="GetToolchain"}`))
+	})
+	It(`Report requests without a response`, func() {
+		metrics := opentoolchainv1.NewMetrics()
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           "http://localhost:1",
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		openToolchainService.SetMetricsCollector(metrics)
+
+		_, _, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).ToNot(BeNil())
+
+		snapshot := metrics.Snapshot()
+		Expect(snapshot.Buckets).To(Equal(opentoolchainv1.DefaultLatencyBuckets))
+		Expect(snapshot.Requests).To(HaveLen(1))
+		Expect(snapshot.Requests[0].StatusClass).To(Equal(opentoolchainv1.StatusClassErrorConst))
+		Expect(snapshot.Requests[0].Errors).To(Equal(int64(1)))
+		Expect(snapshot.Retries).To(BeEmpty())
+	})
+})
@@ -0,0 +1,77 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"net/http"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// RoundTripper : Sends the request built by an operation of the service
+// operationID is the operation name, e.g. "GetToolchain", and result is the value the response body is unmarshalled
+// into, nil when the operation has no response body.
+type RoundTripper interface {
+	RoundTrip(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error)
+}
+
+// RoundTripperFunc : Adapter allowing an ordinary function to be used as a RoundTripper
+type RoundTripperFunc func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error)
+
+// RoundTrip calls f(operationID, request, result)
+func (f RoundTripperFunc) RoundTrip(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+	return f(operationID, request, result)
+}
+
+// Middleware : Wraps the RoundTripper sending the requests of the service
+// A middleware may modify the request before calling next, inspect or replace the response it returns, or answer
+// the request itself without calling next.
+type Middleware func(next RoundTripper) RoundTripper
+
+// Use adds middleware to the chain invoked by every operation of the service
+// Middleware added first is the outermost. Clones made with Clone() inherit the middleware added before they were
+// created; middleware added later only applies to the instance it is added to.
+func (openToolchain *OpenToolchainV1) Use(middleware ...Middleware) {
+	chain := make([]Middleware, 0, len(openToolchain.middleware)+len(middleware))
+	chain = append(chain, openToolchain.middleware...)
+	for _, m := range middleware {
+		if m != nil {
+			chain = append(chain, m)
+		}
+	}
+	openToolchain.middleware = chain
+}
+
+// request sends the request of an operation through the middleware chain, wrapped in the SDK instrumentation.
+func (openToolchain *OpenToolchainV1) request(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+	request = request.WithContext(withOperationState(request.Context()))
+
+	var next RoundTripper = RoundTripperFunc(func(_ string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+		return openToolchain.Service.Request(request, result)
+	})
+	if openToolchain.logger != nil {
+		next = openToolchain.loggingMiddleware(next)
+	}
+	for i := len(openToolchain.middleware) - 1; i >= 0; i-- {
+		next = openToolchain.middleware[i](next)
+	}
+	if openToolchain.metrics != nil {
+		next = openToolchain.metricsMiddleware(next)
+	}
+	next = openToolchain.tracingMiddleware(next)
+	return next.RoundTrip(operationID, request, result)
+}
@@ -0,0 +1,127 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Use(middleware ...Middleware)`, func() {
+	var testServer *httptest.Server
+	var requests int
+
+	BeforeEach(func() {
+		requests = 0
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			requests++
+			Expect(req.Header.Get("X-Audit")).To(Equal("outer,inner"))
+			res.Header().Set("Content-type", "application/json")
+			res.WriteHeader(200)
+			fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain"}]}`)
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	headerMiddleware := func(value string) opentoolchainv1.Middleware {
+		return func(next opentoolchainv1.RoundTripper) opentoolchainv1.RoundTripper {
+			return opentoolchainv1.RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+				header := value
+				if existing := request.Header.Get("X-Audit"); existing != "" {
+					header = existing + "," + value
+				}
+				request.Header.Set("X-Audit", header)
+				return next.RoundTrip(operationID, request, result)
+			})
+		}
+	}
+
+	It(`Invoke middleware with the operation ID`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		var operations []string
+		var statusCodes []int
+		openToolchainService.Use(func(next opentoolchainv1.RoundTripper) opentoolchainv1.RoundTripper {
+			return opentoolchainv1.RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+				operations = append(operations, operationID)
+				response, err := next.RoundTrip(operationID, request, result)
+				statusCodes = append(statusCodes, response.StatusCode)
+				return response, err
+			})
+		}, headerMiddleware("outer"), headerMiddleware("inner"))
+
+		result, response, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		Expect(response).ToNot(BeNil())
+		Expect(*result.Items[0].ToolchainGUID).To(Equal("tc1"))
+		Expect(operations).To(Equal([]string{"GetToolchain"}))
+		Expect(statusCodes).To(Equal([]int{200}))
+	})
+	It(`Answer requests from middleware`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		openToolchainService.Use(func(next opentoolchainv1.RoundTripper) opentoolchainv1.RoundTripper {
+			return opentoolchainv1.RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+				return &core.DetailedResponse{StatusCode: 403}, fmt.Errorf("%s is not allowed", operationID)
+			})
+		})
+
+		response, err := openToolchainService.DeleteToolchain(openToolchainService.NewDeleteToolchainOptions("us-south", "tc1"))
+		Expect(err).To(MatchError("DeleteToolchain is not allowed"))
+		Expect(response.StatusCode).To(Equal(403))
+		Expect(requests).To(Equal(0))
+	})
+	It(`Inherit middleware in clones`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		openToolchainService.Use(headerMiddleware("outer"))
+		clone := openToolchainService.Clone()
+		clone.Use(headerMiddleware("inner"))
+
+		_, _, err := clone.GetToolchain(clone.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(1))
+
+		// Middleware added to the clone does not apply to the original instance
+		openToolchainService.Use(headerMiddleware("inner"))
+		_, _, err = openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(2))
+	})
+})
@@ -32,6 +32,7 @@ import (
 	"github.com/IBM/go-sdk-core/v5/core"
 	common "github.com/dariusbakunas/opentoolchain-go-sdk/common"
 	"github.com/go-openapi/strfmt"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // OpenToolchainV1 : No description provided (generated by Openapi Generator
@@ -40,6 +41,16 @@ import (
 // Version: 1.0.0
 type OpenToolchainV1 struct {
 	Service *core.BaseService
+
+	rateLimiter *RateLimiter
+
+	middleware []Middleware
+
+	tracer trace.Tracer
+
+	metrics MetricsCollector
+
+	logger Logger
 }
 
 // DefaultServiceURL is the default URL to make service requests to.
@@ -53,6 +64,18 @@ type OpenToolchainV1Options struct {
 	ServiceName   string
 	URL           string
 	Authenticator core.Authenticator
+
+	// Limits the rate of requests sent by the service instance and its clones
+	RateLimiter *RateLimiter
+
+	// Provides the tracer recording a span for every operation, defaults to the global tracer provider
+	TracerProvider trace.TracerProvider
+
+	// Receives the measurements of every operation, metrics are not collected when nil
+	Metrics MetricsCollector
+
+	// Receives the requests and responses of every operation, with secrets redacted
+	Logger Logger
 }
 
 // NewOpenToolchainV1UsingExternalConfig : constructs an instance of OpenToolchainV1 with passed in options and external configuration.
@@ -77,6 +100,7 @@ func NewOpenToolchainV1UsingExternalConfig(options *OpenToolchainV1Options) (ope
 	if err != nil {
 		return
 	}
+	openToolchain.configureHTTPClient()
 
 	if options.URL != "" {
 		err = openToolchain.Service.SetServiceURL(options.URL)
@@ -104,8 +128,13 @@ func NewOpenToolchainV1(options *OpenToolchainV1Options) (service *OpenToolchain
 	}
 
 	service = &OpenToolchainV1{
-		Service: baseService,
+		Service:     baseService,
+		rateLimiter: options.RateLimiter,
+		tracer:      newTracer(options.TracerProvider),
+		metrics:     options.Metrics,
+		logger:      options.Logger,
 	}
+	service.configureHTTPClient()
 
 	return
 }
@@ -154,11 +183,13 @@ func (openToolchain *OpenToolchainV1) GetEnableGzipCompression() bool {
 // If either parameter is specified as 0, then a default value is used instead.
 func (openToolchain *OpenToolchainV1) EnableRetries(maxRetries int, maxRetryInterval time.Duration) {
 	openToolchain.Service.EnableRetries(maxRetries, maxRetryInterval)
+	openToolchain.configureHTTPClient()
 }
 
 // DisableRetries disables automatic retries for requests invoked for this service instance.
 func (openToolchain *OpenToolchainV1) DisableRetries() {
 	openToolchain.Service.DisableRetries()
+	openToolchain.configureHTTPClient()
 }
 
 // PatchToolchain : Update toolchain parameters
@@ -217,7 +248,7 @@ func (openToolchain *OpenToolchainV1) PatchToolchainWithContext(ctx context.Cont
 		return
 	}
 
-	response, err = openToolchain.Service.Request(request, nil)
+	response, err = openToolchain.request("PatchToolchain", request, nil)
 
 	return
 }
@@ -270,7 +301,7 @@ func (openToolchain *OpenToolchainV1) DeleteToolchainWithContext(ctx context.Con
 		return
 	}
 
-	response, err = openToolchain.Service.Request(request, nil)
+	response, err = openToolchain.request("DeleteToolchain", request, nil)
 
 	return
 }
@@ -341,7 +372,7 @@ func (openToolchain *OpenToolchainV1) CreateToolchainWithContext(ctx context.Con
 		return
 	}
 
-	response, err = openToolchain.Service.Request(request, nil)
+	response, err = openToolchain.request("CreateToolchain", request, nil)
 
 	return
 }
@@ -404,7 +435,7 @@ func (openToolchain *OpenToolchainV1) CreateServiceInstanceWithContext(ctx conte
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("CreateServiceInstance", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -474,7 +505,7 @@ func (openToolchain *OpenToolchainV1) DeleteServiceInstanceWithContext(ctx conte
 		return
 	}
 
-	response, err = openToolchain.Service.Request(request, nil)
+	response, err = openToolchain.request("DeleteServiceInstance", request, nil)
 
 	return
 }
@@ -539,7 +570,7 @@ func (openToolchain *OpenToolchainV1) PatchServiceInstanceWithContext(ctx contex
 		return
 	}
 
-	response, err = openToolchain.Service.Request(request, nil)
+	response, err = openToolchain.request("PatchServiceInstance", request, nil)
 
 	return
 }
@@ -591,7 +622,7 @@ func (openToolchain *OpenToolchainV1) GetServiceInstanceWithContext(ctx context.
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("GetServiceInstance", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -651,7 +682,7 @@ func (openToolchain *OpenToolchainV1) GetTektonPipelineWithContext(ctx context.C
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("GetTektonPipeline", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -667,6 +698,8 @@ func (openToolchain *OpenToolchainV1) GetTektonPipelineWithContext(ctx context.C
 }
 
 // PatchTektonPipeline : Update tekton pipeline parameters
+// When ExpectedUpdatedAtTimestamp is set, the pipeline is read and compared before the patch is sent. The API has no
+// conditional update, so the check and the patch are not atomic: an update made between the two is not detected.
 func (openToolchain *OpenToolchainV1) PatchTektonPipeline(patchTektonPipelineOptions *PatchTektonPipelineOptions) (result *TektonPipeline, response *core.DetailedResponse, err error) {
 	return openToolchain.PatchTektonPipelineWithContext(context.Background(), patchTektonPipelineOptions)
 }
@@ -682,6 +715,13 @@ func (openToolchain *OpenToolchainV1) PatchTektonPipelineWithContext(ctx context
 		return
 	}
 
+	if patchTektonPipelineOptions.ExpectedUpdatedAtTimestamp != nil {
+		response, err = openToolchain.checkTektonPipelineUnchanged(ctx, patchTektonPipelineOptions)
+		if err != nil {
+			return
+		}
+	}
+
 	pathParamsMap := map[string]string{
 		"guid":   *patchTektonPipelineOptions.GUID,
 		"region": *patchTektonPipelineOptions.Region,
@@ -733,7 +773,7 @@ func (openToolchain *OpenToolchainV1) PatchTektonPipelineWithContext(ctx context
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("PatchTektonPipeline", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -765,7 +805,8 @@ func (openToolchain *OpenToolchainV1) GetTektonPipelineDefinitionWithContext(ctx
 	}
 
 	pathParamsMap := map[string]string{
-		"guid": *getTektonPipelineDefinitionOptions.GUID,
+		"guid":   *getTektonPipelineDefinitionOptions.GUID,
+		"region": *getTektonPipelineDefinitionOptions.Region,
 	}
 
 	builder := core.NewRequestBuilder(core.GET)
@@ -794,7 +835,7 @@ func (openToolchain *OpenToolchainV1) GetTektonPipelineDefinitionWithContext(ctx
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("GetTektonPipelineDefinition", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -826,7 +867,8 @@ func (openToolchain *OpenToolchainV1) CreateTektonPipelineDefinitionWithContext(
 	}
 
 	pathParamsMap := map[string]string{
-		"guid": *createTektonPipelineDefinitionOptions.GUID,
+		"guid":   *createTektonPipelineDefinitionOptions.GUID,
+		"region": *createTektonPipelineDefinitionOptions.Region,
 	}
 
 	builder := core.NewRequestBuilder(core.POST)
@@ -865,7 +907,82 @@ func (openToolchain *OpenToolchainV1) CreateTektonPipelineDefinitionWithContext(
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("CreateTektonPipelineDefinition", request, &rawResponse)
+	if err != nil {
+		return
+	}
+	if rawResponse != nil {
+		err = core.UnmarshalModel(rawResponse, "", &result, UnmarshalCreateTektonPipelineDefinitionResponse)
+		if err != nil {
+			return
+		}
+		response.Result = result
+	}
+
+	return
+}
+
+// UpdateTektonPipelineDefinition : Update tekton pipeline definition
+// Changes the source of a definition input of the pipeline, e.g. its branch or path. Definitions are identified by
+// the shard definition ID of the pipeline input.
+func (openToolchain *OpenToolchainV1) UpdateTektonPipelineDefinition(updateTektonPipelineDefinitionOptions *UpdateTektonPipelineDefinitionOptions) (result *CreateTektonPipelineDefinitionResponse, response *core.DetailedResponse, err error) {
+	return openToolchain.UpdateTektonPipelineDefinitionWithContext(context.Background(), updateTektonPipelineDefinitionOptions)
+}
+
+// UpdateTektonPipelineDefinitionWithContext is an alternate form of the UpdateTektonPipelineDefinition method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) UpdateTektonPipelineDefinitionWithContext(ctx context.Context, updateTektonPipelineDefinitionOptions *UpdateTektonPipelineDefinitionOptions) (result *CreateTektonPipelineDefinitionResponse, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(updateTektonPipelineDefinitionOptions, "updateTektonPipelineDefinitionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(updateTektonPipelineDefinitionOptions, "updateTektonPipelineDefinitionOptions")
+	if err != nil {
+		return
+	}
+
+	pathParamsMap := map[string]string{
+		"guid":          *updateTektonPipelineDefinitionOptions.GUID,
+		"definition_id": *updateTektonPipelineDefinitionOptions.DefinitionID,
+		"region":        *updateTektonPipelineDefinitionOptions.Region,
+	}
+
+	builder := core.NewRequestBuilder(core.PUT)
+	builder = builder.WithContext(ctx)
+	builder.EnableGzipCompression = openToolchain.GetEnableGzipCompression()
+	_, err = builder.ResolveRequestURL(openToolchain.Service.Options.URL, `/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/{guid}/definition/{definition_id}`, pathParamsMap)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range updateTektonPipelineDefinitionOptions.Headers {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	sdkHeaders := common.GetSdkHeaders("open_toolchain", "V1", "UpdateTektonPipelineDefinition")
+	for headerName, headerValue := range sdkHeaders {
+		builder.AddHeader(headerName, headerValue)
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddHeader("Content-Type", "application/json")
+
+	builder.AddQuery("env_id", fmt.Sprint(*updateTektonPipelineDefinitionOptions.EnvID))
+
+	body := make(map[string]interface{})
+	if updateTektonPipelineDefinitionOptions.ScmSource != nil {
+		body["scmSource"] = updateTektonPipelineDefinitionOptions.ScmSource
+	}
+	_, err = builder.SetBodyContentJSON(body)
+	if err != nil {
+		return
+	}
+
+	request, err := builder.Build()
+	if err != nil {
+		return
+	}
+
+	var rawResponse map[string]json.RawMessage
+	response, err = openToolchain.request("UpdateTektonPipelineDefinition", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -880,6 +997,132 @@ func (openToolchain *OpenToolchainV1) CreateTektonPipelineDefinitionWithContext(
 	return
 }
 
+// DeleteTektonPipelineDefinition : Delete tekton pipeline definition
+// Removes a definition input from the pipeline.
+func (openToolchain *OpenToolchainV1) DeleteTektonPipelineDefinition(deleteTektonPipelineDefinitionOptions *DeleteTektonPipelineDefinitionOptions) (response *core.DetailedResponse, err error) {
+	return openToolchain.DeleteTektonPipelineDefinitionWithContext(context.Background(), deleteTektonPipelineDefinitionOptions)
+}
+
+// DeleteTektonPipelineDefinitionWithContext is an alternate form of the DeleteTektonPipelineDefinition method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) DeleteTektonPipelineDefinitionWithContext(ctx context.Context, deleteTektonPipelineDefinitionOptions *DeleteTektonPipelineDefinitionOptions) (response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(deleteTektonPipelineDefinitionOptions, "deleteTektonPipelineDefinitionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(deleteTektonPipelineDefinitionOptions, "deleteTektonPipelineDefinitionOptions")
+	if err != nil {
+		return
+	}
+
+	pathParamsMap := map[string]string{
+		"guid":          *deleteTektonPipelineDefinitionOptions.GUID,
+		"definition_id": *deleteTektonPipelineDefinitionOptions.DefinitionID,
+		"region":        *deleteTektonPipelineDefinitionOptions.Region,
+	}
+
+	builder := core.NewRequestBuilder(core.DELETE)
+	builder = builder.WithContext(ctx)
+	builder.EnableGzipCompression = openToolchain.GetEnableGzipCompression()
+	_, err = builder.ResolveRequestURL(openToolchain.Service.Options.URL, `/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/{guid}/definition/{definition_id}`, pathParamsMap)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range deleteTektonPipelineDefinitionOptions.Headers {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	sdkHeaders := common.GetSdkHeaders("open_toolchain", "V1", "DeleteTektonPipelineDefinition")
+	for headerName, headerValue := range sdkHeaders {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	builder.AddQuery("env_id", fmt.Sprint(*deleteTektonPipelineDefinitionOptions.EnvID))
+
+	request, err := builder.Build()
+	if err != nil {
+		return
+	}
+
+	response, err = openToolchain.request("DeleteTektonPipelineDefinition", request, nil)
+
+	return
+}
+
+// SyncTektonPipelineDefinition : Sync tekton pipeline definition
+// Re-reads the definition of the pipeline from its repositories. Pinned shards are read at the pinned commit SHA, the
+// other shards at the head of their branch.
+func (openToolchain *OpenToolchainV1) SyncTektonPipelineDefinition(syncTektonPipelineDefinitionOptions *SyncTektonPipelineDefinitionOptions) (result *GetTektonPipelineDefinitionResponse, response *core.DetailedResponse, err error) {
+	return openToolchain.SyncTektonPipelineDefinitionWithContext(context.Background(), syncTektonPipelineDefinitionOptions)
+}
+
+// SyncTektonPipelineDefinitionWithContext is an alternate form of the SyncTektonPipelineDefinition method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) SyncTektonPipelineDefinitionWithContext(ctx context.Context, syncTektonPipelineDefinitionOptions *SyncTektonPipelineDefinitionOptions) (result *GetTektonPipelineDefinitionResponse, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(syncTektonPipelineDefinitionOptions, "syncTektonPipelineDefinitionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(syncTektonPipelineDefinitionOptions, "syncTektonPipelineDefinitionOptions")
+	if err != nil {
+		return
+	}
+
+	pathParamsMap := map[string]string{
+		"guid":   *syncTektonPipelineDefinitionOptions.GUID,
+		"region": *syncTektonPipelineDefinitionOptions.Region,
+	}
+
+	builder := core.NewRequestBuilder(core.POST)
+	builder = builder.WithContext(ctx)
+	builder.EnableGzipCompression = openToolchain.GetEnableGzipCompression()
+	_, err = builder.ResolveRequestURL(openToolchain.Service.Options.URL, `/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/{guid}/definition/refresh`, pathParamsMap)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range syncTektonPipelineDefinitionOptions.Headers {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	sdkHeaders := common.GetSdkHeaders("open_toolchain", "V1", "SyncTektonPipelineDefinition")
+	for headerName, headerValue := range sdkHeaders {
+		builder.AddHeader(headerName, headerValue)
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddHeader("Content-Type", "application/json")
+
+	builder.AddQuery("env_id", fmt.Sprint(*syncTektonPipelineDefinitionOptions.EnvID))
+
+	body := make(map[string]interface{})
+	if syncTektonPipelineDefinitionOptions.Pins != nil {
+		body["pins"] = syncTektonPipelineDefinitionOptions.Pins
+	}
+	_, err = builder.SetBodyContentJSON(body)
+	if err != nil {
+		return
+	}
+
+	request, err := builder.Build()
+	if err != nil {
+		return
+	}
+
+	var rawResponse map[string]json.RawMessage
+	response, err = openToolchain.request("SyncTektonPipelineDefinition", request, &rawResponse)
+	if err != nil {
+		return
+	}
+	if rawResponse != nil {
+		err = core.UnmarshalModel(rawResponse, "", &result, UnmarshalGetTektonPipelineDefinitionResponse)
+		if err != nil {
+			return
+		}
+		response.Result = result
+	}
+
+	return
+}
+
 // GetToolchain : Returns details about a particular toolchain
 func (openToolchain *OpenToolchainV1) GetToolchain(getToolchainOptions *GetToolchainOptions) (result *ToolchainResponse, response *core.DetailedResponse, err error) {
 	return openToolchain.GetToolchainWithContext(context.Background(), getToolchainOptions)
@@ -929,7 +1172,7 @@ func (openToolchain *OpenToolchainV1) GetToolchainWithContext(ctx context.Contex
 	}
 
 	var rawResponse map[string]json.RawMessage
-	response, err = openToolchain.Service.Request(request, &rawResponse)
+	response, err = openToolchain.request("GetToolchain", request, &rawResponse)
 	if err != nil {
 		return
 	}
@@ -1241,6 +1484,9 @@ type CreateTektonPipelineDefinitionOptions struct {
 	// GUID of the pipeline.
 	GUID *string `validate:"required,ne="`
 
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
 	// Environment ID.
 	EnvID *string `validate:"required"`
 
@@ -1251,10 +1497,11 @@ type CreateTektonPipelineDefinitionOptions struct {
 }
 
 // NewCreateTektonPipelineDefinitionOptions : Instantiate CreateTektonPipelineDefinitionOptions
-func (*OpenToolchainV1) NewCreateTektonPipelineDefinitionOptions(guid string, envID string) *CreateTektonPipelineDefinitionOptions {
+func (*OpenToolchainV1) NewCreateTektonPipelineDefinitionOptions(guid string, region string, envID string) *CreateTektonPipelineDefinitionOptions {
 	return &CreateTektonPipelineDefinitionOptions{
-		GUID:  core.StringPtr(guid),
-		EnvID: core.StringPtr(envID),
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		EnvID:  core.StringPtr(envID),
 	}
 }
 
@@ -1264,6 +1511,12 @@ func (options *CreateTektonPipelineDefinitionOptions) SetGUID(guid string) *Crea
 	return options
 }
 
+// SetRegion : Allow user to set Region
+func (options *CreateTektonPipelineDefinitionOptions) SetRegion(region string) *CreateTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
 // SetEnvID : Allow user to set EnvID
 func (options *CreateTektonPipelineDefinitionOptions) SetEnvID(envID string) *CreateTektonPipelineDefinitionOptions {
 	options.EnvID = core.StringPtr(envID)
@@ -1562,6 +1815,64 @@ func (options *DeleteServiceInstanceOptions) SetHeaders(param map[string]string)
 	return options
 }
 
+// DeleteTektonPipelineDefinitionOptions : The DeleteTektonPipelineDefinition options.
+type DeleteTektonPipelineDefinitionOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// ID of the definition, the shard definition ID of the pipeline input.
+	DefinitionID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDeleteTektonPipelineDefinitionOptions : Instantiate DeleteTektonPipelineDefinitionOptions
+func (*OpenToolchainV1) NewDeleteTektonPipelineDefinitionOptions(guid string, definitionID string, region string, envID string) *DeleteTektonPipelineDefinitionOptions {
+	return &DeleteTektonPipelineDefinitionOptions{
+		GUID:         core.StringPtr(guid),
+		DefinitionID: core.StringPtr(definitionID),
+		Region:       core.StringPtr(region),
+		EnvID:        core.StringPtr(envID),
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *DeleteTektonPipelineDefinitionOptions) SetGUID(guid string) *DeleteTektonPipelineDefinitionOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetDefinitionID : Allow user to set DefinitionID
+func (options *DeleteTektonPipelineDefinitionOptions) SetDefinitionID(definitionID string) *DeleteTektonPipelineDefinitionOptions {
+	options.DefinitionID = core.StringPtr(definitionID)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *DeleteTektonPipelineDefinitionOptions) SetRegion(region string) *DeleteTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *DeleteTektonPipelineDefinitionOptions) SetEnvID(envID string) *DeleteTektonPipelineDefinitionOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DeleteTektonPipelineDefinitionOptions) SetHeaders(param map[string]string) *DeleteTektonPipelineDefinitionOptions {
+	options.Headers = param
+	return options
+}
+
 // DeleteToolchainOptions : The DeleteToolchain options.
 type DeleteToolchainOptions struct {
 	// Toolchain region.
@@ -1751,6 +2062,9 @@ type GetTektonPipelineDefinitionOptions struct {
 	// GUID of the pipeline.
 	GUID *string `validate:"required,ne="`
 
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
 	// Environment ID.
 	EnvID *string `validate:"required"`
 
@@ -1759,10 +2073,11 @@ type GetTektonPipelineDefinitionOptions struct {
 }
 
 // NewGetTektonPipelineDefinitionOptions : Instantiate GetTektonPipelineDefinitionOptions
-func (*OpenToolchainV1) NewGetTektonPipelineDefinitionOptions(guid string, envID string) *GetTektonPipelineDefinitionOptions {
+func (*OpenToolchainV1) NewGetTektonPipelineDefinitionOptions(guid string, region string, envID string) *GetTektonPipelineDefinitionOptions {
 	return &GetTektonPipelineDefinitionOptions{
-		GUID:  core.StringPtr(guid),
-		EnvID: core.StringPtr(envID),
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		EnvID:  core.StringPtr(envID),
 	}
 }
 
@@ -1772,6 +2087,12 @@ func (options *GetTektonPipelineDefinitionOptions) SetGUID(guid string) *GetTekt
 	return options
 }
 
+// SetRegion : Allow user to set Region
+func (options *GetTektonPipelineDefinitionOptions) SetRegion(region string) *GetTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
 // SetEnvID : Allow user to set EnvID
 func (options *GetTektonPipelineDefinitionOptions) SetEnvID(envID string) *GetTektonPipelineDefinitionOptions {
 	options.EnvID = core.StringPtr(envID)
@@ -1889,6 +2210,7 @@ type GetToolchainOptions struct {
 	GUID *string `validate:"required,ne="`
 
 	// Instructs the API to return the specified content according to the comma-separated list of sections.
+	// Supported sections are fields, container, template and services, see ToolchainIncludes.
 	Include *string
 
 	// Allows users to set headers on API requests
@@ -2179,6 +2501,13 @@ type PatchTektonPipelineOptions struct {
 
 	PipelineDefinitionID *string
 
+	// UpdatedAtTimestamp of the pipeline the patch is based on. When set, the pipeline is read again before it is
+	// patched and a *TektonPipelineConflictError is returned if it was updated in the meantime.
+	ExpectedUpdatedAtTimestamp *float64
+
+	// Version of the pipeline the patch is based on, returned as the Base of a *TektonPipelineConflictError.
+	Base *TektonPipeline `validate:"-"`
+
 	// Allows users to set headers on API requests
 	Headers map[string]string
 }
@@ -2233,6 +2562,21 @@ func (options *PatchTektonPipelineOptions) SetPipelineDefinitionID(pipelineDefin
 	return options
 }
 
+// SetExpectedUpdatedAtTimestamp : Allow user to set ExpectedUpdatedAtTimestamp
+func (options *PatchTektonPipelineOptions) SetExpectedUpdatedAtTimestamp(expectedUpdatedAtTimestamp float64) *PatchTektonPipelineOptions {
+	options.ExpectedUpdatedAtTimestamp = core.Float64Ptr(expectedUpdatedAtTimestamp)
+	return options
+}
+
+// SetBase : Allow user to set Base and its UpdatedAtTimestamp as ExpectedUpdatedAtTimestamp
+func (options *PatchTektonPipelineOptions) SetBase(base *TektonPipeline) *PatchTektonPipelineOptions {
+	options.Base = base
+	if base != nil && base.UpdatedAtTimestamp != nil {
+		options.ExpectedUpdatedAtTimestamp = core.Float64Ptr(*base.UpdatedAtTimestamp)
+	}
+	return options
+}
+
 // SetHeaders : Allow user to set Headers
 func (options *PatchTektonPipelineOptions) SetHeaders(param map[string]string) *PatchTektonPipelineOptions {
 	options.Headers = param
@@ -2470,6 +2814,38 @@ func UnmarshalServiceToolchainBindingStatus(m map[string]json.RawMessage, result
 	return
 }
 
+// ShardPin : ShardPin struct
+type ShardPin struct {
+	ShardDefinitionID *string `json:"shardDefinitionId" validate:"required"`
+
+	Sha *string `json:"sha" validate:"required"`
+}
+
+// NewShardPin : Instantiate ShardPin (Generic Model Constructor)
+func (*OpenToolchainV1) NewShardPin(shardDefinitionID string, sha string) (model *ShardPin, err error) {
+	model = &ShardPin{
+		ShardDefinitionID: core.StringPtr(shardDefinitionID),
+		Sha:               core.StringPtr(sha),
+	}
+	err = core.ValidateStruct(model, "required parameters")
+	return
+}
+
+// UnmarshalShardPin unmarshals an instance of ShardPin from the specified map of raw messages.
+func UnmarshalShardPin(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(ShardPin)
+	err = core.UnmarshalPrimitive(m, "shardDefinitionId", &obj.ShardDefinitionID)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "sha", &obj.Sha)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
 // ShardRepo : ShardRepo struct
 type ShardRepo struct {
 	Sha *string `json:"sha,omitempty"`
@@ -2504,6 +2880,62 @@ func UnmarshalShardRepo(m map[string]json.RawMessage, result interface{}) (err e
 	return
 }
 
+// SyncTektonPipelineDefinitionOptions : The SyncTektonPipelineDefinition options.
+type SyncTektonPipelineDefinitionOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	Pins []ShardPin
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewSyncTektonPipelineDefinitionOptions : Instantiate SyncTektonPipelineDefinitionOptions
+func (*OpenToolchainV1) NewSyncTektonPipelineDefinitionOptions(guid string, region string, envID string) *SyncTektonPipelineDefinitionOptions {
+	return &SyncTektonPipelineDefinitionOptions{
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		EnvID:  core.StringPtr(envID),
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *SyncTektonPipelineDefinitionOptions) SetGUID(guid string) *SyncTektonPipelineDefinitionOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *SyncTektonPipelineDefinitionOptions) SetRegion(region string) *SyncTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *SyncTektonPipelineDefinitionOptions) SetEnvID(envID string) *SyncTektonPipelineDefinitionOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetPins : Allow user to set Pins
+func (options *SyncTektonPipelineDefinitionOptions) SetPins(pins []ShardPin) *SyncTektonPipelineDefinitionOptions {
+	options.Pins = pins
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *SyncTektonPipelineDefinitionOptions) SetHeaders(param map[string]string) *SyncTektonPipelineDefinitionOptions {
+	options.Headers = param
+	return options
+}
+
 // TektonPipeline : TektonPipeline struct
 type TektonPipeline struct {
 	Name *string `json:"name" validate:"required"`
@@ -2545,6 +2977,8 @@ type TektonPipeline struct {
 	ToolchainCRN *string `json:"toolchainCRN,omitempty"`
 
 	PipelineDefinitionID *string `json:"pipelineDefinitionId,omitempty"`
+
+	Worker *TektonPipelineWorker `json:"worker,omitempty"`
 }
 
 // UnmarshalTektonPipeline unmarshals an instance of TektonPipeline from the specified map of raw messages.
@@ -2630,6 +3064,10 @@ func UnmarshalTektonPipeline(m map[string]json.RawMessage, result interface{}) (
 	if err != nil {
 		return
 	}
+	err = core.UnmarshalModel(m, "worker", &obj.Worker, UnmarshalTektonPipelineWorker)
+	if err != nil {
+		return
+	}
 	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
 	return
 }
@@ -2838,36 +3276,80 @@ func UnmarshalTektonPipelineTriggerScmSource(m map[string]json.RawMessage, resul
 	return
 }
 
+// TektonPipelineWorker : TektonPipelineWorker struct
+type TektonPipelineWorker struct {
+	WorkerID *string `json:"workerId,omitempty"`
+
+	WorkerName *string `json:"workerName,omitempty"`
+
+	WorkerType *string `json:"workerType,omitempty"`
+}
+
+// UnmarshalTektonPipelineWorker unmarshals an instance of TektonPipelineWorker from the specified map of raw messages.
+func UnmarshalTektonPipelineWorker(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(TektonPipelineWorker)
+	err = core.UnmarshalPrimitive(m, "workerId", &obj.WorkerID)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "workerName", &obj.WorkerName)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "workerType", &obj.WorkerType)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
 // Toolchain : Toolchain struct
+// The fields populated depend on the sections included in the GetToolchain request, see ToolchainIncludes.
 type Toolchain struct {
+	// Always populated.
 	ToolchainGUID *string `json:"toolchain_guid" validate:"required"`
 
+	// Always populated.
 	Name *string `json:"name" validate:"required"`
 
+	// Populated when the fields section is included.
 	Description *string `json:"description,omitempty"`
 
+	// Populated when the fields section is included.
 	Key *string `json:"key,omitempty"`
 
+	// Populated when the container section is included.
 	Container *Container `json:"container,omitempty"`
 
+	// Populated when the fields section is included.
 	CRN *string `json:"crn,omitempty"`
 
+	// Populated when the fields section is included.
 	Created *strfmt.DateTime `json:"created,omitempty"`
 
+	// Populated when the fields section is included.
 	UpdatedAt *strfmt.DateTime `json:"updated_at,omitempty"`
 
+	// Populated when the fields section is included.
 	Creator *string `json:"creator,omitempty"`
 
+	// Populated when the fields section is included.
 	Generator *string `json:"generator,omitempty"`
 
+	// Populated when the template section is included.
 	Template *ToolchainTemplate `json:"template,omitempty"`
 
+	// Populated when the fields section is included.
 	Tags []string `json:"tags,omitempty"`
 
+	// Populated when the fields section is included.
 	LifecycleMessagingWebhookID *string `json:"lifecycle_messaging_webhook_id,omitempty"`
 
+	// Populated when the fields section is included.
 	RegionID *string `json:"region_id,omitempty"`
 
+	// Populated when the services section is included.
 	Services []Service `json:"services,omitempty"`
 }
 
@@ -3011,3 +3493,69 @@ func UnmarshalToolchainTemplate(m map[string]json.RawMessage, result interface{}
 	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
 	return
 }
+
+// UpdateTektonPipelineDefinitionOptions : The UpdateTektonPipelineDefinition options.
+type UpdateTektonPipelineDefinitionOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// ID of the definition, the shard definition ID of the pipeline input.
+	DefinitionID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	ScmSource *CreateTektonPipelineDefinitionParamsInputsItemScmSource
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewUpdateTektonPipelineDefinitionOptions : Instantiate UpdateTektonPipelineDefinitionOptions
+func (*OpenToolchainV1) NewUpdateTektonPipelineDefinitionOptions(guid string, definitionID string, region string, envID string) *UpdateTektonPipelineDefinitionOptions {
+	return &UpdateTektonPipelineDefinitionOptions{
+		GUID:         core.StringPtr(guid),
+		DefinitionID: core.StringPtr(definitionID),
+		Region:       core.StringPtr(region),
+		EnvID:        core.StringPtr(envID),
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *UpdateTektonPipelineDefinitionOptions) SetGUID(guid string) *UpdateTektonPipelineDefinitionOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetDefinitionID : Allow user to set DefinitionID
+func (options *UpdateTektonPipelineDefinitionOptions) SetDefinitionID(definitionID string) *UpdateTektonPipelineDefinitionOptions {
+	options.DefinitionID = core.StringPtr(definitionID)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *UpdateTektonPipelineDefinitionOptions) SetRegion(region string) *UpdateTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *UpdateTektonPipelineDefinitionOptions) SetEnvID(envID string) *UpdateTektonPipelineDefinitionOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetScmSource : Allow user to set ScmSource
+func (options *UpdateTektonPipelineDefinitionOptions) SetScmSource(scmSource *CreateTektonPipelineDefinitionParamsInputsItemScmSource) *UpdateTektonPipelineDefinitionOptions {
+	options.ScmSource = scmSource
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *UpdateTektonPipelineDefinitionOptions) SetHeaders(param map[string]string) *UpdateTektonPipelineDefinitionOptions {
+	options.Headers = param
+	return options
+}
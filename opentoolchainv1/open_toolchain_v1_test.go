@@ -2057,7 +2057,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 		})
 	})
 	Describe(`GetTektonPipelineDefinition(getTektonPipelineDefinitionOptions *GetTektonPipelineDefinitionOptions) - Operation response error`, func() {
-		getTektonPipelineDefinitionPath := "/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
+		getTektonPipelineDefinitionPath := "/devops-api.testString.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
 		Context(`Using mock server endpoint with invalid JSON response`, func() {
 			BeforeEach(func() {
 				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
@@ -2083,6 +2083,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				getTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.GetTektonPipelineDefinitionOptions)
 				getTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				getTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				getTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
 				// Expect response parsing to fail since we are receiving a text/plain response
@@ -2104,7 +2105,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 		})
 	})
 	Describe(`GetTektonPipelineDefinition(getTektonPipelineDefinitionOptions *GetTektonPipelineDefinitionOptions)`, func() {
-		getTektonPipelineDefinitionPath := "/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
+		getTektonPipelineDefinitionPath := "/devops-api.testString.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
 		Context(`Using mock server endpoint with timeout`, func() {
 			BeforeEach(func() {
 				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
@@ -2136,6 +2137,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				getTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.GetTektonPipelineDefinitionOptions)
 				getTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				getTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				getTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
 
@@ -2197,6 +2199,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				getTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.GetTektonPipelineDefinitionOptions)
 				getTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				getTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				getTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
 
@@ -2218,6 +2221,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				getTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.GetTektonPipelineDefinitionOptions)
 				getTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				getTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				getTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
 				// Invoke operation with empty URL (negative test)
@@ -2260,6 +2264,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				getTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.GetTektonPipelineDefinitionOptions)
 				getTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				getTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				getTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
 
@@ -2277,7 +2282,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 		})
 	})
 	Describe(`CreateTektonPipelineDefinition(createTektonPipelineDefinitionOptions *CreateTektonPipelineDefinitionOptions) - Operation response error`, func() {
-		createTektonPipelineDefinitionPath := "/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
+		createTektonPipelineDefinitionPath := "/devops-api.testString.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
 		Context(`Using mock server endpoint with invalid JSON response`, func() {
 			BeforeEach(func() {
 				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
@@ -2318,6 +2323,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				createTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.CreateTektonPipelineDefinitionOptions)
 				createTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				createTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				createTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.Inputs = []opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}
 				createTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
@@ -2340,7 +2346,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 		})
 	})
 	Describe(`CreateTektonPipelineDefinition(createTektonPipelineDefinitionOptions *CreateTektonPipelineDefinitionOptions)`, func() {
-		createTektonPipelineDefinitionPath := "/devops-api.{region}.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
+		createTektonPipelineDefinitionPath := "/devops-api.testString.devops.cloud.ibm.com/v1/tekton-pipelines/testString/definition"
 		Context(`Using mock server endpoint with timeout`, func() {
 			BeforeEach(func() {
 				testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
@@ -2403,6 +2409,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				createTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.CreateTektonPipelineDefinitionOptions)
 				createTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				createTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				createTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.Inputs = []opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}
 				createTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
@@ -2496,6 +2503,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				createTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.CreateTektonPipelineDefinitionOptions)
 				createTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				createTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				createTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.Inputs = []opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}
 				createTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
@@ -2533,6 +2541,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				createTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.CreateTektonPipelineDefinitionOptions)
 				createTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				createTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				createTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.Inputs = []opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}
 				createTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
@@ -2591,6 +2600,7 @@ var _ = Describe(`OpenToolchainV1`, func() {
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				createTektonPipelineDefinitionOptionsModel := new(opentoolchainv1.CreateTektonPipelineDefinitionOptions)
 				createTektonPipelineDefinitionOptionsModel.GUID = core.StringPtr("testString")
+				createTektonPipelineDefinitionOptionsModel.Region = core.StringPtr("testString")
 				createTektonPipelineDefinitionOptionsModel.EnvID = core.StringPtr("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.Inputs = []opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}
 				createTektonPipelineDefinitionOptionsModel.Headers = map[string]string{"x-custom-header": "x-custom-value"}
@@ -2952,14 +2962,17 @@ var _ = Describe(`OpenToolchainV1`, func() {
 
 				// Construct an instance of the CreateTektonPipelineDefinitionOptions model
 				guid := "testString"
+				region := "testString"
 				envID := "ibm:yp:us-south"
-				createTektonPipelineDefinitionOptionsModel := openToolchainService.NewCreateTektonPipelineDefinitionOptions(guid, envID)
+				createTektonPipelineDefinitionOptionsModel := openToolchainService.NewCreateTektonPipelineDefinitionOptions(guid, region, envID)
 				createTektonPipelineDefinitionOptionsModel.SetGUID("testString")
+				createTektonPipelineDefinitionOptionsModel.SetRegion("testString")
 				createTektonPipelineDefinitionOptionsModel.SetEnvID("ibm:yp:us-south")
 				createTektonPipelineDefinitionOptionsModel.SetInputs([]opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel})
 				createTektonPipelineDefinitionOptionsModel.SetHeaders(map[string]string{"foo": "bar"})
 				Expect(createTektonPipelineDefinitionOptionsModel).ToNot(BeNil())
 				Expect(createTektonPipelineDefinitionOptionsModel.GUID).To(Equal(core.StringPtr("testString")))
+				Expect(createTektonPipelineDefinitionOptionsModel.Region).To(Equal(core.StringPtr("testString")))
 				Expect(createTektonPipelineDefinitionOptionsModel.EnvID).To(Equal(core.StringPtr("ibm:yp:us-south")))
 				Expect(createTektonPipelineDefinitionOptionsModel.Inputs).To(Equal([]opentoolchainv1.CreateTektonPipelineDefinitionParamsInputsItem{*createTektonPipelineDefinitionParamsInputsItemModel}))
 				Expect(createTektonPipelineDefinitionOptionsModel.Headers).To(Equal(map[string]string{"foo": "bar"}))
@@ -3042,13 +3055,16 @@ var _ = Describe(`OpenToolchainV1`, func() {
 			It(`Invoke NewGetTektonPipelineDefinitionOptions successfully`, func() {
 				// Construct an instance of the GetTektonPipelineDefinitionOptions model
 				guid := "testString"
+				region := "testString"
 				envID := "ibm:yp:us-south"
-				getTektonPipelineDefinitionOptionsModel := openToolchainService.NewGetTektonPipelineDefinitionOptions(guid, envID)
+				getTektonPipelineDefinitionOptionsModel := openToolchainService.NewGetTektonPipelineDefinitionOptions(guid, region, envID)
 				getTektonPipelineDefinitionOptionsModel.SetGUID("testString")
+				getTektonPipelineDefinitionOptionsModel.SetRegion("testString")
 				getTektonPipelineDefinitionOptionsModel.SetEnvID("ibm:yp:us-south")
 				getTektonPipelineDefinitionOptionsModel.SetHeaders(map[string]string{"foo": "bar"})
 				Expect(getTektonPipelineDefinitionOptionsModel).ToNot(BeNil())
 				Expect(getTektonPipelineDefinitionOptionsModel.GUID).To(Equal(core.StringPtr("testString")))
+				Expect(getTektonPipelineDefinitionOptionsModel.Region).To(Equal(core.StringPtr("testString")))
 				Expect(getTektonPipelineDefinitionOptionsModel.EnvID).To(Equal(core.StringPtr("ibm:yp:us-south")))
 				Expect(getTektonPipelineDefinitionOptionsModel.Headers).To(Equal(map[string]string{"foo": "bar"}))
 			})
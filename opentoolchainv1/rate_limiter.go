@@ -0,0 +1,165 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter : Token bucket limiting the rate of requests sent to the service
+// Every HTTP request, including retries, takes a token. When the service answers 429 or 503 with a Retry-After
+// header, the limiter stops handing out tokens until the requested time has passed. A RateLimiter is safe for
+// concurrent use and can be shared by several OpenToolchainV1 instances; clones made with Clone() share the limiter
+// of the original instance.
+type RateLimiter struct {
+	mutex sync.Mutex
+
+	// Tokens added per second, 0 if the rate is not limited.
+	rate float64
+
+	// Maximum number of tokens in the bucket.
+	burst float64
+
+	tokens float64
+	last   time.Time
+
+	pausedUntil time.Time
+}
+
+// NewRateLimiter : Instantiate RateLimiter
+// requestsPerSecond is the sustained request rate and burst the number of requests that can be sent at once after
+// a period of inactivity. A burst lower than 1 is treated as 1. A requestsPerSecond of 0 or less does not limit the
+// rate, the limiter then only honors the pauses requested with Pause.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	if requestsPerSecond < 0 || math.IsNaN(requestsPerSecond) {
+		requestsPerSecond = 0
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	_, err := limiter.wait(ctx)
+	return err
+}
+
+// wait is Wait, also returning whether the caller had to wait for a token.
+func (limiter *RateLimiter) wait(ctx context.Context) (waited bool, err error) {
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		limiter.mutex.Lock()
+		now := time.Now()
+		if pause := limiter.pausedUntil.Sub(now); pause > 0 {
+			limiter.mutex.Unlock()
+			waited = true
+			if err = sleepWithContext(ctx, pause); err != nil {
+				return
+			}
+			continue
+		}
+
+		if limiter.rate == 0 {
+			limiter.mutex.Unlock()
+			return
+		}
+
+		limiter.refill(now)
+		limiter.tokens--
+		var delay time.Duration
+		if limiter.tokens < 0 {
+			delay = time.Duration(math.Ceil(-limiter.tokens / limiter.rate * float64(time.Second)))
+		}
+		limiter.mutex.Unlock()
+
+		if delay == 0 {
+			return
+		}
+		waited = true
+		if err = sleepWithContext(ctx, delay); err != nil {
+			// Give the reserved token back.
+			limiter.mutex.Lock()
+			limiter.tokens++
+			limiter.mutex.Unlock()
+		}
+		return
+	}
+}
+
+// Pause stops handing out tokens for the specified duration
+// Pauses do not shorten one another: the limiter resumes at the latest requested time.
+func (limiter *RateLimiter) Pause(d time.Duration) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	if until := time.Now().Add(d); until.After(limiter.pausedUntil) {
+		limiter.pausedUntil = until
+	}
+}
+
+// waitResumed blocks until the limiter is no longer paused or the context is done, without taking a token.
+func (limiter *RateLimiter) waitResumed(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		limiter.mutex.Lock()
+		pause := time.Until(limiter.pausedUntil)
+		limiter.mutex.Unlock()
+		if pause <= 0 {
+			return nil
+		}
+		if err := sleepWithContext(ctx, pause); err != nil {
+			return err
+		}
+	}
+}
+
+// refill adds the tokens accumulated since the last call. The caller must hold the mutex.
+func (limiter *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(limiter.last).Seconds()
+	limiter.last = now
+	if elapsed <= 0 {
+		return
+	}
+	limiter.tokens = math.Min(limiter.burst, limiter.tokens+elapsed*limiter.rate)
+}
+
+// sleepWithContext waits for the specified duration or until the context is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,154 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RateLimiter`, func() {
+	var testServer *httptest.Server
+	var requests int32
+
+	Describe(`NewRateLimiter(requestsPerSecond float64, burst int)`, func() {
+		It(`Limit the request rate`, func() {
+			limiter := opentoolchainv1.NewRateLimiter(50, 1)
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				Expect(limiter.Wait(context.Background())).To(Succeed())
+			}
+			Expect(time.Since(start)).To(BeNumerically(">=", 55*time.Millisecond))
+		})
+		It(`Allow a burst of requests`, func() {
+			limiter := opentoolchainv1.NewRateLimiter(1, 3)
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				Expect(limiter.Wait(context.Background())).To(Succeed())
+			}
+			Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
+		})
+		It(`Stop waiting when the context is done`, func() {
+			limiter := opentoolchainv1.NewRateLimiter(0.1, 1)
+			Expect(limiter.Wait(context.Background())).To(Succeed())
+
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancelFunc()
+			Expect(limiter.Wait(ctx)).To(Equal(context.DeadlineExceeded))
+		})
+		It(`Do not limit the rate when requestsPerSecond is not positive`, func() {
+			for _, requestsPerSecond := range []float64{0, -1} {
+				limiter := opentoolchainv1.NewRateLimiter(requestsPerSecond, 0)
+				ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+				for i := 0; i < 100; i++ {
+					Expect(limiter.Wait(ctx)).To(Succeed())
+				}
+				cancelFunc()
+			}
+
+			// Pauses are still honored
+			limiter := opentoolchainv1.NewRateLimiter(0, 1)
+			limiter.Pause(50 * time.Millisecond)
+			start := time.Now()
+			Expect(limiter.Wait(context.Background())).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 45*time.Millisecond))
+		})
+		It(`Pause the limiter`, func() {
+			limiter := opentoolchainv1.NewRateLimiter(1000, 10)
+			limiter.Pause(50 * time.Millisecond)
+			start := time.Now()
+			Expect(limiter.Wait(context.Background())).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 45*time.Millisecond))
+		})
+	})
+
+	Describe(`Rate limited service requests`, func() {
+		BeforeEach(func() {
+			atomic.StoreInt32(&requests, 0)
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				res.Header().Set("Content-type", "application/json")
+				if atomic.AddInt32(&requests, 1) == 1 && req.URL.Query().Get("include") == "throttle" {
+					res.Header().Set("Retry-After", "1")
+					res.WriteHeader(429)
+					fmt.Fprint(res, `{"message": "too many requests"}`)
+					return
+				}
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 0, "items": []}`)
+			}))
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+		It(`Share the rate limiter with clones`, func() {
+			limiter := opentoolchainv1.NewRateLimiter(20, 1)
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+				RateLimiter:   limiter,
+			})
+			Expect(serviceErr).To(BeNil())
+			clone := openToolchainService.Clone()
+			Expect(clone.GetRateLimiter()).To(BeIdenticalTo(limiter))
+
+			getToolchainOptionsModel := openToolchainService.NewGetToolchainOptions("us-south", "tc1")
+			start := time.Now()
+			_, _, err := openToolchainService.GetToolchain(getToolchainOptionsModel)
+			Expect(err).To(BeNil())
+			_, _, err = clone.GetToolchain(getToolchainOptionsModel)
+			Expect(err).To(BeNil())
+			_, _, err = openToolchainService.GetToolchain(getToolchainOptionsModel)
+			Expect(err).To(BeNil())
+			Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+		})
+		It(`Keep the rate limiter when retries are enabled and honor Retry-After`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+			openToolchainService.SetRateLimiter(opentoolchainv1.NewRateLimiter(100, 5))
+			openToolchainService.EnableRetries(2, 10*time.Millisecond)
+
+			getToolchainOptionsModel := openToolchainService.NewGetToolchainOptions("us-south", "tc1")
+			getToolchainOptionsModel.SetInclude("throttle")
+			start := time.Now()
+			_, response, err := openToolchainService.GetToolchain(getToolchainOptionsModel)
+			Expect(err).To(BeNil())
+			Expect(response.StatusCode).To(Equal(200))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+			Expect(time.Since(start)).To(BeNumerically(">=", 900*time.Millisecond))
+
+			openToolchainService.SetRateLimiter(nil)
+			Expect(openToolchainService.GetRateLimiter()).To(BeNil())
+			openToolchainService.DisableRetries()
+			_, _, err = openToolchainService.GetToolchain(getToolchainOptionsModel)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,303 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Constants associated with the RepoUsage.Source property.
+const (
+	RepoUsageSourceToolConst      = "tool"
+	RepoUsageSourceInputConst     = "input"
+	RepoUsageSourceTriggerConst   = "trigger"
+	RepoUsageSourceShardRepoConst = "shard_repo"
+)
+
+// NormalizeRepoURL : Reduce a repository URL to the host and path identifying the repository
+// The scheme, user, port, trailing slash and .git suffix are dropped and the URL is lowercased, so that the HTTPS and
+// SSH forms of a repository URL, e.g. https://github.com/org/app and git@github.com:org/app.git, are equal.
+func NormalizeRepoURL(repoURL string) string {
+	repoURL = strings.ToLower(strings.TrimSpace(repoURL))
+	if index := strings.Index(repoURL, "://"); index >= 0 {
+		repoURL = repoURL[index+3:]
+	} else if colon := strings.Index(repoURL, ":"); colon >= 0 && !strings.Contains(repoURL[:colon], "/") {
+		// scp-like syntax, user@host:path
+		repoURL = repoURL[:colon] + "/" + repoURL[colon+1:]
+	}
+
+	host, path := repoURL, ""
+	if slash := strings.Index(repoURL, "/"); slash >= 0 {
+		host, path = repoURL[:slash], repoURL[slash:]
+	}
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+	if colon := strings.Index(host, ":"); colon >= 0 {
+		host = host[:colon]
+	}
+
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	return host + strings.TrimSuffix(path, "/")
+}
+
+// RepoUsage : A reference to a repository in a toolchain
+type RepoUsage struct {
+	Toolchain ToolchainLocator `json:"toolchain"`
+
+	// One of the RepoUsageSource constants.
+	Source string `json:"source"`
+
+	// ID of the pipeline holding the reference, empty for tool integrations.
+	PipelineID string `json:"pipeline_id,omitempty"`
+
+	// ID of the trigger or shard definition holding the reference.
+	ID string `json:"id,omitempty"`
+
+	// Tool integration of the repository, or referenced by the input or the trigger.
+	ServiceInstanceID string `json:"service_instance_id,omitempty"`
+
+	// Repository URL as configured.
+	RepoURL string `json:"repo_url"`
+}
+
+// String returns the location of the reference
+func (usage *RepoUsage) String() string {
+	location := fmt.Sprintf("%s %s", usage.Toolchain.Region, usage.Toolchain.GUID)
+	if usage.PipelineID != "" {
+		location += " pipeline " + usage.PipelineID
+	}
+	location += " " + usage.Source
+	id := usage.ID
+	if id == "" {
+		id = usage.ServiceInstanceID
+	}
+	if id != "" {
+		location += " " + id
+	}
+	return location + ": " + usage.RepoURL
+}
+
+// RepoUsageReport : The result of searching toolchains for a repository
+type RepoUsageReport struct {
+	RepoURL string `json:"repo_url"`
+
+	// Number of toolchains searched successfully.
+	Scanned int `json:"scanned"`
+
+	// References in the order of the toolchains searched.
+	Usages []RepoUsage `json:"usages"`
+
+	Errors []AuditError `json:"errors"`
+}
+
+// PipelineIDs returns the IDs of the pipelines referencing the repository
+func (report *RepoUsageReport) PipelineIDs() []string {
+	pipelineIDs := []string{}
+	seen := map[string]bool{}
+	for _, usage := range report.Usages {
+		if usage.PipelineID != "" && !seen[usage.PipelineID] {
+			seen[usage.PipelineID] = true
+			pipelineIDs = append(pipelineIDs, usage.PipelineID)
+		}
+	}
+	return pipelineIDs
+}
+
+// Print writes one line per reference, then one line per toolchain that could not be searched
+func (report *RepoUsageReport) Print(w io.Writer) error {
+	for i := range report.Usages {
+		if _, err := fmt.Fprintln(w, report.Usages[i].String()); err != nil {
+			return err
+		}
+	}
+	for _, searchErr := range report.Errors {
+		if _, err := fmt.Fprintf(w, "%s %s: error: %s\n", searchErr.Toolchain.Region, searchErr.Toolchain.GUID, searchErr.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report to w as indented JSON
+func (report *RepoUsageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// FindRepoUsagesInGraph : List the references to a repository in a toolchain graph
+// The repository URL is compared after NormalizeRepoURL with the repo_url parameter of the tool integrations, the URL
+// of the pipeline inputs and triggers and the repository URL of the pipeline definition shards. Inputs and triggers
+// without URL use the repository of the tool integration they reference.
+func FindRepoUsagesInGraph(graph *ToolchainGraph, locator ToolchainLocator, repoURL string) []RepoUsage {
+	usages := []RepoUsage{}
+	if graph == nil {
+		return usages
+	}
+	normalized := NormalizeRepoURL(repoURL)
+	matches := func(url *string) bool {
+		return url != nil && *url != "" && NormalizeRepoURL(*url) == normalized
+	}
+
+	for _, node := range graph.Tools {
+		if url, ok := node.Service.Parameters["repo_url"].(string); ok && matches(&url) {
+			usages = append(usages, RepoUsage{
+				Toolchain:         locator,
+				Source:            RepoUsageSourceToolConst,
+				ServiceInstanceID: stringValue(node.Service.InstanceID),
+				RepoURL:           url,
+			})
+		}
+	}
+	for _, pipeline := range graph.Pipelines {
+		for _, input := range pipeline.Inputs {
+			var url *string
+			if input.ScmSource != nil {
+				url = input.ScmSource.URL
+			}
+			if resolved := graph.repoURL(url, input.ServiceInstanceID); matches(&resolved) {
+				usages = append(usages, RepoUsage{
+					Toolchain:         locator,
+					Source:            RepoUsageSourceInputConst,
+					PipelineID:        stringValue(pipeline.ID),
+					ID:                stringValue(input.ShardDefinitionID),
+					ServiceInstanceID: stringValue(input.ServiceInstanceID),
+					RepoURL:           resolved,
+				})
+			}
+		}
+		for _, trigger := range pipeline.Triggers {
+			var url *string
+			if trigger.ScmSource != nil {
+				url = trigger.ScmSource.URL
+			}
+			if resolved := graph.repoURL(url, trigger.ServiceInstanceID); matches(&resolved) {
+				usages = append(usages, RepoUsage{
+					Toolchain:         locator,
+					Source:            RepoUsageSourceTriggerConst,
+					PipelineID:        stringValue(pipeline.ID),
+					ID:                stringValue(trigger.ID),
+					ServiceInstanceID: stringValue(trigger.ServiceInstanceID),
+					RepoURL:           resolved,
+				})
+			}
+		}
+	}
+	for _, definition := range graph.Definitions {
+		for _, shardRepo := range definition.ShardRepos {
+			if matches(shardRepo.RepoURL) {
+				usages = append(usages, RepoUsage{
+					Toolchain:  locator,
+					Source:     RepoUsageSourceShardRepoConst,
+					PipelineID: stringValue(definition.PipelineID),
+					ID:         stringValue(shardRepo.ShardDefinitionID),
+					RepoURL:    *shardRepo.RepoURL,
+				})
+			}
+		}
+	}
+	return usages
+}
+
+// FindRepoUsages : Search a set of toolchains for references to a repository
+// Each toolchain, in any region, is fetched with GetToolchainGraph and searched with FindRepoUsagesInGraph.
+// Toolchains that cannot be fetched are recorded in the report errors and do not stop the search.
+func (openToolchain *OpenToolchainV1) FindRepoUsages(findRepoUsagesOptions *FindRepoUsagesOptions) (result *RepoUsageReport, err error) {
+	return openToolchain.FindRepoUsagesWithContext(context.Background(), findRepoUsagesOptions)
+}
+
+// FindRepoUsagesWithContext is an alternate form of the FindRepoUsages method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) FindRepoUsagesWithContext(ctx context.Context, findRepoUsagesOptions *FindRepoUsagesOptions) (result *RepoUsageReport, err error) {
+	err = core.ValidateNotNil(findRepoUsagesOptions, "findRepoUsagesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(findRepoUsagesOptions, "findRepoUsagesOptions")
+	if err != nil {
+		return
+	}
+
+	result = &RepoUsageReport{
+		RepoURL: *findRepoUsagesOptions.RepoURL,
+		Usages:  []RepoUsage{},
+		Errors:  []AuditError{},
+	}
+	for _, locator := range findRepoUsagesOptions.Toolchains {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		getToolchainGraphOptions := openToolchain.NewGetToolchainGraphOptions(locator.Region, locator.GUID, locator.EnvID)
+		getToolchainGraphOptions.SetHeaders(findRepoUsagesOptions.Headers)
+		graph, graphErr := openToolchain.GetToolchainGraphWithContext(ctx, getToolchainGraphOptions)
+		if graphErr != nil {
+			result.Errors = append(result.Errors, AuditError{Toolchain: locator, Message: graphErr.Error()})
+			continue
+		}
+
+		result.Scanned++
+		result.Usages = append(result.Usages, FindRepoUsagesInGraph(graph, locator, *findRepoUsagesOptions.RepoURL)...)
+	}
+
+	return
+}
+
+// FindRepoUsagesOptions : The FindRepoUsages options.
+type FindRepoUsagesOptions struct {
+	// URL of the repository, in HTTPS or SSH form.
+	RepoURL *string `validate:"required,ne="`
+
+	// Toolchains to search.
+	Toolchains []ToolchainLocator `validate:"required,min=1"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewFindRepoUsagesOptions : Instantiate FindRepoUsagesOptions
+func (*OpenToolchainV1) NewFindRepoUsagesOptions(repoURL string, toolchains []ToolchainLocator) *FindRepoUsagesOptions {
+	return &FindRepoUsagesOptions{
+		RepoURL:    core.StringPtr(repoURL),
+		Toolchains: toolchains,
+	}
+}
+
+// SetRepoURL : Allow user to set RepoURL
+func (options *FindRepoUsagesOptions) SetRepoURL(repoURL string) *FindRepoUsagesOptions {
+	options.RepoURL = core.StringPtr(repoURL)
+	return options
+}
+
+// SetToolchains : Allow user to set Toolchains
+func (options *FindRepoUsagesOptions) SetToolchains(toolchains []ToolchainLocator) *FindRepoUsagesOptions {
+	options.Toolchains = toolchains
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *FindRepoUsagesOptions) SetHeaders(param map[string]string) *FindRepoUsagesOptions {
+	options.Headers = param
+	return options
+}
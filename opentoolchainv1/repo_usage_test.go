@@ -0,0 +1,154 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RepoUsage`, func() {
+	var testServer *httptest.Server
+
+	Describe(`NormalizeRepoURL(repoURL string)`, func() {
+		It(`Normalize HTTPS and SSH forms`, func() {
+			for _, repoURL := range []string{
+				"https://github.com/org/app",
+				"https://GitHub.com/Org/App.git",
+				"https://github.com/org/app/",
+				"https://user@github.com/org/app.git",
+				"git@github.com:org/app.git",
+				"ssh://git@github.com:22/org/app",
+				"github.com/org/app",
+			} {
+				Expect(opentoolchainv1.NormalizeRepoURL(repoURL)).To(Equal("github.com/org/app"), repoURL)
+			}
+			Expect(opentoolchainv1.NormalizeRepoURL("https://github.com/org/app.github")).To(Equal("github.com/org/app.github"))
+			Expect(opentoolchainv1.NormalizeRepoURL("")).To(Equal(""))
+		})
+	})
+
+	Describe(`FindRepoUsages(findRepoUsagesOptions *FindRepoUsagesOptions)`, func() {
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				path := req.URL.Path
+				res.Header().Set("Content-type", "application/json")
+				switch {
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "services": [
+						{"service_id": "pipeline", "instance_id": "pl1", "parameters": {"type": "tekton"}},
+						{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"repo_url": "https://github.com/Org/App.git"}}
+					]}]}`)
+				case path == "/devops-api.eu-de.devops.cloud.ibm.com/v1/toolchains/tc2":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc2", "services": [
+						{"service_id": "pipeline", "instance_id": "pl2", "parameters": {"type": "tekton"}},
+						{"service_id": "githubconsolidated", "instance_id": "repo2", "parameters": {"repo_url": "https://github.com/org/other"}},
+						{"service_id": "githubconsolidated", "instance_id": "repo3", "parameters": {"repo_url": "https://github.com/org/app/"}}
+					]}]}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl1", "name": "pl1", "toolchainId": "tc1", "envProperties": [],
+						"inputs": [{"serviceInstanceId": "repo1", "shardDefinitionId": "s1", "scmSource": {"url": "git@github.com:org/app.git"}}],
+						"triggers": [
+							{"id": "t1", "eventListener": "l", "type": "scm", "serviceInstanceId": "repo1", "scmSource": {"url": "https://github.com/org/app"}},
+							{"id": "t2", "eventListener": "l", "type": "scm", "scmSource": {"url": "https://github.com/org/other"}}
+						]}`)
+				case path == "/devops-api.eu-de.devops.cloud.ibm.com/v1/tekton-pipelines/pl2":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl2", "name": "pl2", "toolchainId": "tc2", "envProperties": [],
+						"triggers": [{"id": "t3", "eventListener": "l", "type": "scm", "serviceInstanceId": "repo3", "scmSource": {"branch": "main"}}]}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"pipelineId": "pl1", "id": "d1", "shardRepos": [{"shardDefinitionId": "s1", "repoUrl": "ssh://git@github.com:22/org/app"}]}`)
+				case path == "/devops-api.eu-de.devops.cloud.ibm.com/v1/tekton-pipelines/pl2/definition":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"pipelineId": "pl2", "id": "d2", "shardRepos": [{"shardDefinitionId": "s2", "repoUrl": "https://github.com/org/other"}]}`)
+				default:
+					res.WriteHeader(404)
+					fmt.Fprint(res, `{"message": "not found"}`)
+				}
+			}))
+		})
+		It(`Invoke FindRepoUsages successfully`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			findRepoUsagesOptionsModel := openToolchainService.NewFindRepoUsagesOptions("git@github.com:org/app.git", []opentoolchainv1.ToolchainLocator{
+				{Region: "us-south", GUID: "tc1", EnvID: "ibm:yp:us-south"},
+				{Region: "eu-de", GUID: "tc2", EnvID: "ibm:yp:eu-de"},
+				{Region: "eu-de", GUID: "tc3", EnvID: "ibm:yp:eu-de"},
+			})
+			report, err := openToolchainService.FindRepoUsages(findRepoUsagesOptionsModel)
+			Expect(err).To(BeNil())
+			Expect(report.Scanned).To(Equal(2))
+			Expect(report.Errors).To(HaveLen(1))
+			Expect(report.Errors[0].Toolchain.GUID).To(Equal("tc3"))
+			Expect(report.Usages).To(HaveLen(6))
+			Expect(report.PipelineIDs()).To(Equal([]string{"pl1", "pl2"}))
+
+			var output bytes.Buffer
+			Expect(report.Print(&output)).To(Succeed())
+			Expect(output.String()).To(HavePrefix(`us-south tc1 tool repo1: https://github.com/Org/App.git
+us-south tc1 pipeline pl1 input s1: git@github.com:org/app.git
+us-south tc1 pipeline pl1 trigger t1: https://github.com/org/app
+us-south tc1 pipeline pl1 shard_repo s1: ssh://git@github.com:22/org/app
+eu-de tc2 tool repo3: https://github.com/org/app/
+eu-de tc2 pipeline pl2 trigger t3: https://github.com/org/app/
+eu-de tc3: error: `))
+
+			output.Reset()
+			Expect(report.WriteJSON(&output)).To(Succeed())
+			var decoded map[string]interface{}
+			Expect(json.Unmarshal(output.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded["repo_url"]).To(Equal("git@github.com:org/app.git"))
+			Expect(decoded["usages"]).To(HaveLen(6))
+		})
+		It(`Invoke FindRepoUsages with error: Operation validation error`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			report, err := openToolchainService.FindRepoUsages(nil)
+			Expect(err).ToNot(BeNil())
+			Expect(report).To(BeNil())
+
+			report, err = openToolchainService.FindRepoUsages(openToolchainService.NewFindRepoUsagesOptions("https://github.com/org/app", nil))
+			Expect(err).ToNot(BeNil())
+			Expect(report).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,541 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	common "github.com/dariusbakunas/opentoolchain-go-sdk/common"
+)
+
+// Constants associated with the AttachTagsOptions.TagType and DetachTagsOptions.TagType properties.
+// User tags organize resources, access tags are used in access management policies.
+const (
+	TagTypeUserConst   = "user"
+	TagTypeAccessConst = "access"
+)
+
+// searchToolchainsPageSize is the number of toolchains requested per page by ListToolchainsByTag.
+const searchToolchainsPageSize = 100
+
+// ToolIntegrationCRN returns the CRN of a tool integration from the CRN of its toolchain and its service instance ID.
+// Tags are attached to tool integrations through this CRN.
+func ToolIntegrationCRN(toolchainCRN string, instanceID string) (string, error) {
+	crn, err := parseToolchainCRN(toolchainCRN)
+	if err != nil {
+		return "", err
+	}
+	toolCRN, err := crn.ToolIntegration(instanceID)
+	if err != nil {
+		return "", err
+	}
+	return toolCRN.String(), nil
+}
+
+// AttachTags : Attach tags to toolchains and tool integrations
+// Tags are attached with the IBM Cloud Global Tagging API to the resources identified by their CRN: Toolchain.CRN
+// for toolchains and ToolIntegrationCRN for tool integrations. Tags that do not exist are created.
+func (openToolchain *OpenToolchainV1) AttachTags(attachTagsOptions *AttachTagsOptions) (result *TagResults, response *core.DetailedResponse, err error) {
+	return openToolchain.AttachTagsWithContext(context.Background(), attachTagsOptions)
+}
+
+// AttachTagsWithContext is an alternate form of the AttachTags method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) AttachTagsWithContext(ctx context.Context, attachTagsOptions *AttachTagsOptions) (result *TagResults, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(attachTagsOptions, "attachTagsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(attachTagsOptions, "attachTagsOptions")
+	if err != nil {
+		return
+	}
+
+	return openToolchain.updateTags(ctx, "AttachTags", "attach", attachTagsOptions.Resources, attachTagsOptions.TagNames, attachTagsOptions.TagType, attachTagsOptions.Headers)
+}
+
+// DetachTags : Detach tags from toolchains and tool integrations
+// Tags are detached with the IBM Cloud Global Tagging API from the resources identified by their CRN.
+func (openToolchain *OpenToolchainV1) DetachTags(detachTagsOptions *DetachTagsOptions) (result *TagResults, response *core.DetailedResponse, err error) {
+	return openToolchain.DetachTagsWithContext(context.Background(), detachTagsOptions)
+}
+
+// DetachTagsWithContext is an alternate form of the DetachTags method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) DetachTagsWithContext(ctx context.Context, detachTagsOptions *DetachTagsOptions) (result *TagResults, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(detachTagsOptions, "detachTagsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(detachTagsOptions, "detachTagsOptions")
+	if err != nil {
+		return
+	}
+
+	return openToolchain.updateTags(ctx, "DetachTags", "detach", detachTagsOptions.Resources, detachTagsOptions.TagNames, detachTagsOptions.TagType, detachTagsOptions.Headers)
+}
+
+// updateTags attaches or detaches tags with the Global Tagging API.
+func (openToolchain *OpenToolchainV1) updateTags(ctx context.Context, operationID string, action string, resources []string, tagNames []string, tagType *string, headers map[string]string) (result *TagResults, response *core.DetailedResponse, err error) {
+	pathParamsMap := map[string]string{
+		"action": action,
+	}
+
+	builder := core.NewRequestBuilder(core.POST)
+	builder = builder.WithContext(ctx)
+	builder.EnableGzipCompression = openToolchain.GetEnableGzipCompression()
+	_, err = builder.ResolveRequestURL(openToolchain.Service.Options.URL, `/tags.global-search-tagging.cloud.ibm.com/v3/tags/{action}`, pathParamsMap)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range headers {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	sdkHeaders := common.GetSdkHeaders("open_toolchain", "V1", operationID)
+	for headerName, headerValue := range sdkHeaders {
+		builder.AddHeader(headerName, headerValue)
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddHeader("Content-Type", "application/json")
+
+	if tagType != nil {
+		builder.AddQuery("tag_type", fmt.Sprint(*tagType))
+	}
+
+	resourceList := []map[string]string{}
+	for _, resource := range resources {
+		resourceList = append(resourceList, map[string]string{"resource_id": resource})
+	}
+	body := make(map[string]interface{})
+	body["resources"] = resourceList
+	body["tag_names"] = tagNames
+	_, err = builder.SetBodyContentJSON(body)
+	if err != nil {
+		return
+	}
+
+	request, err := builder.Build()
+	if err != nil {
+		return
+	}
+
+	var rawResponse map[string]json.RawMessage
+	response, err = openToolchain.request(operationID, request, &rawResponse)
+	if err != nil {
+		return
+	}
+	if rawResponse != nil {
+		err = core.UnmarshalModel(rawResponse, "", &result, UnmarshalTagResults)
+		if err != nil {
+			return
+		}
+		response.Result = result
+	}
+
+	if result != nil {
+		failed := []string{}
+		for _, tagResult := range result.Results {
+			if tagResult.IsError != nil && *tagResult.IsError {
+				failed = append(failed, stringValue(tagResult.ResourceID))
+			}
+		}
+		if len(failed) > 0 {
+			err = fmt.Errorf("error updating the tags of %s", strings.Join(failed, ", "))
+		}
+	}
+	return
+}
+
+// ListToolchainsByTag : List the toolchains carrying all the specified tags
+// Toolchains are searched with the IBM Cloud Global Search API, page by page, and can be restricted to a region.
+func (openToolchain *OpenToolchainV1) ListToolchainsByTag(listToolchainsByTagOptions *ListToolchainsByTagOptions) (result *TaggedToolchainList, response *core.DetailedResponse, err error) {
+	return openToolchain.ListToolchainsByTagWithContext(context.Background(), listToolchainsByTagOptions)
+}
+
+// ListToolchainsByTagWithContext is an alternate form of the ListToolchainsByTag method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) ListToolchainsByTagWithContext(ctx context.Context, listToolchainsByTagOptions *ListToolchainsByTagOptions) (result *TaggedToolchainList, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listToolchainsByTagOptions, "listToolchainsByTagOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(listToolchainsByTagOptions, "listToolchainsByTagOptions")
+	if err != nil {
+		return
+	}
+
+	tagField := "tags"
+	if listToolchainsByTagOptions.TagType != nil && *listToolchainsByTagOptions.TagType == TagTypeAccessConst {
+		tagField = "access_tags"
+	}
+	clauses := []string{"service_name:toolchain", "type:toolchain"}
+	for _, tagName := range listToolchainsByTagOptions.TagNames {
+		clauses = append(clauses, fmt.Sprintf("%s:%s", tagField, searchQuote(tagName)))
+	}
+	if listToolchainsByTagOptions.Region != nil && *listToolchainsByTagOptions.Region != "" {
+		clauses = append(clauses, fmt.Sprintf("region:%s", searchQuote(*listToolchainsByTagOptions.Region)))
+	}
+	query := strings.Join(clauses, " AND ")
+
+	result = &TaggedToolchainList{Items: []TaggedToolchain{}}
+	var searchCursor *string
+	for {
+		var page *TaggedToolchainList
+		page, response, err = openToolchain.searchToolchains(ctx, query, searchCursor, listToolchainsByTagOptions.Headers)
+		if err != nil {
+			return
+		}
+		if page == nil {
+			break
+		}
+		result.Items = append(result.Items, page.Items...)
+		if len(page.Items) < searchToolchainsPageSize || page.SearchCursor == nil || *page.SearchCursor == "" {
+			break
+		}
+		searchCursor = page.SearchCursor
+	}
+	if response != nil {
+		response.Result = result
+	}
+	return
+}
+
+// searchToolchains returns a page of search results.
+func (openToolchain *OpenToolchainV1) searchToolchains(ctx context.Context, query string, searchCursor *string, headers map[string]string) (result *TaggedToolchainList, response *core.DetailedResponse, err error) {
+	builder := core.NewRequestBuilder(core.POST)
+	builder = builder.WithContext(ctx)
+	builder.EnableGzipCompression = openToolchain.GetEnableGzipCompression()
+	_, err = builder.ResolveRequestURL(openToolchain.Service.Options.URL, `/api.global-search-tagging.cloud.ibm.com/v3/resources/search`, nil)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range headers {
+		builder.AddHeader(headerName, headerValue)
+	}
+
+	sdkHeaders := common.GetSdkHeaders("open_toolchain", "V1", "ListToolchainsByTag")
+	for headerName, headerValue := range sdkHeaders {
+		builder.AddHeader(headerName, headerValue)
+	}
+	builder.AddHeader("Accept", "application/json")
+	builder.AddHeader("Content-Type", "application/json")
+
+	builder.AddQuery("limit", fmt.Sprint(searchToolchainsPageSize))
+
+	body := make(map[string]interface{})
+	body["query"] = query
+	body["fields"] = []string{"name", "region", "tags", "access_tags"}
+	if searchCursor != nil {
+		body["search_cursor"] = searchCursor
+	}
+	_, err = builder.SetBodyContentJSON(body)
+	if err != nil {
+		return
+	}
+
+	request, err := builder.Build()
+	if err != nil {
+		return
+	}
+
+	var rawResponse map[string]json.RawMessage
+	response, err = openToolchain.request("ListToolchainsByTag", request, &rawResponse)
+	if err != nil {
+		return
+	}
+	if rawResponse != nil {
+		err = core.UnmarshalModel(rawResponse, "", &result, UnmarshalTaggedToolchainList)
+	}
+	return
+}
+
+// searchQuote quotes a value of a Global Search query.
+func searchQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// AttachTagsOptions : The AttachTags options.
+type AttachTagsOptions struct {
+	// CRNs of the toolchains and tool integrations.
+	Resources []string `validate:"required,min=1"`
+
+	// Tags to attach.
+	TagNames []string `validate:"required,min=1"`
+
+	// Type of the tags, one of the TagType constants. Defaults to user tags.
+	TagType *string
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewAttachTagsOptions : Instantiate AttachTagsOptions
+func (*OpenToolchainV1) NewAttachTagsOptions(resources []string, tagNames []string) *AttachTagsOptions {
+	return &AttachTagsOptions{
+		Resources: resources,
+		TagNames:  tagNames,
+	}
+}
+
+// SetResources : Allow user to set Resources
+func (options *AttachTagsOptions) SetResources(resources []string) *AttachTagsOptions {
+	options.Resources = resources
+	return options
+}
+
+// SetTagNames : Allow user to set TagNames
+func (options *AttachTagsOptions) SetTagNames(tagNames []string) *AttachTagsOptions {
+	options.TagNames = tagNames
+	return options
+}
+
+// SetTagType : Allow user to set TagType
+func (options *AttachTagsOptions) SetTagType(tagType string) *AttachTagsOptions {
+	options.TagType = core.StringPtr(tagType)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *AttachTagsOptions) SetHeaders(param map[string]string) *AttachTagsOptions {
+	options.Headers = param
+	return options
+}
+
+// DetachTagsOptions : The DetachTags options.
+type DetachTagsOptions struct {
+	// CRNs of the toolchains and tool integrations.
+	Resources []string `validate:"required,min=1"`
+
+	// Tags to detach.
+	TagNames []string `validate:"required,min=1"`
+
+	// Type of the tags, one of the TagType constants. Defaults to user tags.
+	TagType *string
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDetachTagsOptions : Instantiate DetachTagsOptions
+func (*OpenToolchainV1) NewDetachTagsOptions(resources []string, tagNames []string) *DetachTagsOptions {
+	return &DetachTagsOptions{
+		Resources: resources,
+		TagNames:  tagNames,
+	}
+}
+
+// SetResources : Allow user to set Resources
+func (options *DetachTagsOptions) SetResources(resources []string) *DetachTagsOptions {
+	options.Resources = resources
+	return options
+}
+
+// SetTagNames : Allow user to set TagNames
+func (options *DetachTagsOptions) SetTagNames(tagNames []string) *DetachTagsOptions {
+	options.TagNames = tagNames
+	return options
+}
+
+// SetTagType : Allow user to set TagType
+func (options *DetachTagsOptions) SetTagType(tagType string) *DetachTagsOptions {
+	options.TagType = core.StringPtr(tagType)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DetachTagsOptions) SetHeaders(param map[string]string) *DetachTagsOptions {
+	options.Headers = param
+	return options
+}
+
+// ListToolchainsByTagOptions : The ListToolchainsByTag options.
+type ListToolchainsByTagOptions struct {
+	// Tags the toolchains must carry.
+	TagNames []string `validate:"required,min=1"`
+
+	// Toolchain region. All regions are searched when not set.
+	Region *string
+
+	// Type of the tags, one of the TagType constants. Defaults to user tags.
+	TagType *string
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewListToolchainsByTagOptions : Instantiate ListToolchainsByTagOptions
+func (*OpenToolchainV1) NewListToolchainsByTagOptions(tagNames []string) *ListToolchainsByTagOptions {
+	return &ListToolchainsByTagOptions{
+		TagNames: tagNames,
+	}
+}
+
+// SetTagNames : Allow user to set TagNames
+func (options *ListToolchainsByTagOptions) SetTagNames(tagNames []string) *ListToolchainsByTagOptions {
+	options.TagNames = tagNames
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *ListToolchainsByTagOptions) SetRegion(region string) *ListToolchainsByTagOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetTagType : Allow user to set TagType
+func (options *ListToolchainsByTagOptions) SetTagType(tagType string) *ListToolchainsByTagOptions {
+	options.TagType = core.StringPtr(tagType)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ListToolchainsByTagOptions) SetHeaders(param map[string]string) *ListToolchainsByTagOptions {
+	options.Headers = param
+	return options
+}
+
+// TagResults : TagResults struct
+type TagResults struct {
+	Results []TagResult `json:"results,omitempty"`
+}
+
+// UnmarshalTagResults unmarshals an instance of TagResults from the specified map of raw messages.
+func UnmarshalTagResults(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(TagResults)
+	err = core.UnmarshalModel(m, "results", &obj.Results, UnmarshalTagResult)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
+// TagResult : TagResult struct
+type TagResult struct {
+	// CRN of the resource.
+	ResourceID *string `json:"resource_id,omitempty"`
+
+	IsError *bool `json:"is_error,omitempty"`
+
+	Message *string `json:"message,omitempty"`
+}
+
+// UnmarshalTagResult unmarshals an instance of TagResult from the specified map of raw messages.
+func UnmarshalTagResult(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(TagResult)
+	err = core.UnmarshalPrimitive(m, "resource_id", &obj.ResourceID)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "is_error", &obj.IsError)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "message", &obj.Message)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
+// TaggedToolchainList : TaggedToolchainList struct
+type TaggedToolchainList struct {
+	SearchCursor *string `json:"search_cursor,omitempty"`
+
+	Items []TaggedToolchain `json:"items"`
+}
+
+// UnmarshalTaggedToolchainList unmarshals an instance of TaggedToolchainList from the specified map of raw messages.
+func UnmarshalTaggedToolchainList(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(TaggedToolchainList)
+	err = core.UnmarshalPrimitive(m, "search_cursor", &obj.SearchCursor)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalModel(m, "items", &obj.Items, UnmarshalTaggedToolchain)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
+// GUIDs returns the GUIDs of the toolchains, which can be passed to GetToolchain
+func (list *TaggedToolchainList) GUIDs() []string {
+	guids := []string{}
+	for i := range list.Items {
+		if guid := list.Items[i].GUID(); guid != "" {
+			guids = append(guids, guid)
+		}
+	}
+	return guids
+}
+
+// TaggedToolchain : TaggedToolchain struct
+type TaggedToolchain struct {
+	CRN *string `json:"crn,omitempty"`
+
+	Name *string `json:"name,omitempty"`
+
+	Region *string `json:"region,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+
+	AccessTags []string `json:"access_tags,omitempty"`
+}
+
+// UnmarshalTaggedToolchain unmarshals an instance of TaggedToolchain from the specified map of raw messages.
+func UnmarshalTaggedToolchain(m map[string]json.RawMessage, result interface{}) (err error) {
+	obj := new(TaggedToolchain)
+	err = core.UnmarshalPrimitive(m, "crn", &obj.CRN)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "name", &obj.Name)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "region", &obj.Region)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "tags", &obj.Tags)
+	if err != nil {
+		return
+	}
+	err = core.UnmarshalPrimitive(m, "access_tags", &obj.AccessTags)
+	if err != nil {
+		return
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(obj))
+	return
+}
+
+// GUID returns the toolchain GUID, derived from its CRN
+func (toolchain *TaggedToolchain) GUID() string {
+	crn, err := ParseCRN(stringValue(toolchain.CRN))
+	if err != nil {
+		return ""
+	}
+	return crn.ToolchainGUID()
+}
@@ -0,0 +1,153 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`Tagging`, func() {
+	const toolchainCRN = "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc1::"
+
+	var testServer *httptest.Server
+	var requestBodies []map[string]interface{}
+
+	BeforeEach(func() {
+		requestBodies = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("POST"))
+			var body map[string]interface{}
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			requestBodies = append(requestBodies, body)
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.URL.Path {
+			case "/tags.global-search-tagging.cloud.ibm.com/v3/tags/attach":
+				Expect(req.URL.Query().Get("tag_type")).To(Equal("access"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"results": [{"resource_id": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc1::", "is_error": false}]}`)
+			case "/tags.global-search-tagging.cloud.ibm.com/v3/tags/detach":
+				Expect(req.URL.Query()).ToNot(HaveKey("tag_type"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"results": [{"resource_id": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc1::", "is_error": false},
+					{"resource_id": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc1:tool:missing", "is_error": true, "message": "not found"}]}`)
+			case "/api.global-search-tagging.cloud.ibm.com/v3/resources/search":
+				Expect(req.URL.Query().Get("limit")).To(Equal("100"))
+				res.WriteHeader(200)
+				items := []string{}
+				start := 0
+				if body["search_cursor"] != nil {
+					Expect(body["search_cursor"]).To(Equal("page2"))
+					start = 100
+				}
+				for i := start; i < start+100 && i < 101; i++ {
+					items = append(items, fmt.Sprintf(`{"crn": "crn:v1:bluemix:public:toolchain:us-south:a/acct:tc%d::", "name": "toolchain %d", "region": "us-south", "tags": ["team:a"]}`, i, i))
+				}
+				fmt.Fprintf(res, `{"search_cursor": "page2", "limit": 100, "items": [%s]}`, strings.Join(items, ","))
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke ToolIntegrationCRN successfully`, func() {
+		crn, err := opentoolchainv1.ToolIntegrationCRN(toolchainCRN, "repo1")
+		Expect(err).To(BeNil())
+		Expect(crn).To(Equal("crn:v1:bluemix:public:toolchain:us-south:a/acct:tc1:tool:repo1"))
+
+		_, err = opentoolchainv1.ToolIntegrationCRN("crn:v1:bluemix:public:cloud-object-storage:global:a/acct:cos1::", "repo1")
+		Expect(err).ToNot(BeNil())
+	})
+	It(`Invoke AttachTags and DetachTags successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		attachTagsOptionsModel := openToolchainService.NewAttachTagsOptions([]string{toolchainCRN}, []string{"project:billing"})
+		attachTagsOptionsModel.SetTagType(opentoolchainv1.TagTypeAccessConst)
+		result, response, err := openToolchainService.AttachTags(attachTagsOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(result.Results).To(HaveLen(1))
+		Expect(requestBodies[0]).To(Equal(map[string]interface{}{
+			"resources": []interface{}{map[string]interface{}{"resource_id": toolchainCRN}},
+			"tag_names": []interface{}{"project:billing"},
+		}))
+
+		// Failures of individual resources are reported as an error along with the results
+		toolCRN, _ := opentoolchainv1.ToolIntegrationCRN(toolchainCRN, "missing")
+		result, _, err = openToolchainService.DetachTags(openToolchainService.NewDetachTagsOptions([]string{toolchainCRN, toolCRN}, []string{"team:a"}))
+		Expect(err).To(MatchError("error updating the tags of " + toolCRN))
+		Expect(result.Results).To(HaveLen(2))
+		Expect(*result.Results[1].Message).To(Equal("not found"))
+	})
+	It(`Invoke ListToolchainsByTag successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		listToolchainsByTagOptionsModel := openToolchainService.NewListToolchainsByTagOptions([]string{"team:a", `say "hi"`})
+		listToolchainsByTagOptionsModel.SetRegion("us-south")
+		result, response, err := openToolchainService.ListToolchainsByTag(listToolchainsByTagOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(result.Items).To(HaveLen(101))
+		Expect(result.GUIDs()[100]).To(Equal("tc100"))
+		Expect(*result.Items[0].Name).To(Equal("toolchain 0"))
+		Expect(result.Items[0].Tags).To(Equal([]string{"team:a"}))
+
+		Expect(requestBodies).To(HaveLen(2))
+		Expect(requestBodies[0]["query"]).To(Equal(`service_name:toolchain AND type:toolchain AND tags:"team:a" AND tags:"say \"hi\"" AND region:"us-south"`))
+		Expect(requestBodies[0]).ToNot(HaveKey("search_cursor"))
+	})
+	It(`Invoke tagging operations with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		_, response, err := openToolchainService.AttachTags(openToolchainService.NewAttachTagsOptions([]string{toolchainCRN}, []string{}))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		_, response, err = openToolchainService.DetachTags(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		_, response, err = openToolchainService.ListToolchainsByTag(openToolchainService.NewListToolchainsByTagOptions(nil))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(requestBodies).To(BeEmpty())
+	})
+})
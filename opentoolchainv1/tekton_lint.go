@@ -0,0 +1,416 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Constants associated with the TektonLintIssue.Kind property.
+const (
+	TektonLintIssueKindMissingCheckoutConst        = "missing_checkout"
+	TektonLintIssueKindMissingPathConst            = "missing_path"
+	TektonLintIssueKindInvalidYAMLConst            = "invalid_yaml"
+	TektonLintIssueKindDuplicateResourceConst      = "duplicate_resource"
+	TektonLintIssueKindMissingEventListenerConst   = "missing_event_listener"
+	TektonLintIssueKindMissingTriggerTemplateConst = "missing_trigger_template"
+	TektonLintIssueKindMissingTriggerBindingConst  = "missing_trigger_binding"
+	TektonLintIssueKindUnsatisfiedParamConst       = "unsatisfied_param"
+	TektonLintIssueKindMissingPipelineConst        = "missing_pipeline"
+	TektonLintIssueKindMissingTaskConst            = "missing_task"
+)
+
+// Kinds of the Tekton resources read by the linter.
+const (
+	tektonKindEventListener   = "EventListener"
+	tektonKindTriggerTemplate = "TriggerTemplate"
+	tektonKindTriggerBinding  = "TriggerBinding"
+	tektonKindPipeline        = "Pipeline"
+	tektonKindTask            = "Task"
+)
+
+// TektonLintReport : Issues found in the Tekton definition of a pipeline
+type TektonLintReport struct {
+	// Issues in the order they were found: inputs first, then triggers, then pipelines and tasks.
+	Issues []TektonLintIssue `json:"issues"`
+}
+
+// TektonLintIssue : A single issue found in the Tekton definition of a pipeline
+type TektonLintIssue struct {
+	// One of the TektonLintIssueKind constants.
+	Kind string `json:"kind"`
+
+	// File the issue was found in, relative to the checkout, empty for issues of the pipeline configuration.
+	File string `json:"file,omitempty"`
+
+	// Resource the issue was found in, e.g. EventListener/listener.
+	Resource string `json:"resource,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// String returns the issue prefixed by its location
+func (issue *TektonLintIssue) String() string {
+	location := issue.File
+	if issue.Resource != "" {
+		if location != "" {
+			location += " "
+		}
+		location += issue.Resource
+	}
+	if location == "" {
+		return issue.Message
+	}
+	return location + ": " + issue.Message
+}
+
+// IsEmpty returns true if no issue was found
+func (report *TektonLintReport) IsEmpty() bool {
+	return len(report.Issues) == 0
+}
+
+// Print writes one line per issue to w
+func (report *TektonLintReport) Print(w io.Writer) error {
+	if report.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No issues")
+		return err
+	}
+	for i := range report.Issues {
+		if _, err := fmt.Fprintln(w, report.Issues[i].String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LintTektonDefinition : Lint the Tekton definition of a pipeline read from a local checkout
+// Every definition input of the pipeline is read from its path in the checkout, see LintTektonDefinitionCheckouts.
+func LintTektonDefinition(pipeline *TektonPipeline, checkoutDir string) (*TektonLintReport, error) {
+	checkouts := map[string]string{}
+	for _, input := range pipeline.Inputs {
+		checkouts[stringValue(input.ServiceInstanceID)] = checkoutDir
+	}
+	return LintTektonDefinitionCheckouts(pipeline, checkouts)
+}
+
+// LintTektonDefinitionCheckouts : Lint the Tekton definition of a pipeline read from local checkouts
+// Checkouts maps the service instance IDs of the repositories of the pipeline inputs to local directories, the YAML
+// files found under the path of each input are read. The linter verifies that the EventListener of every trigger
+// exists along with the TriggerTemplates and TriggerBindings it references, that the params of the TriggerTemplates
+// without default are provided by the environment properties of the pipeline or the TriggerBindings of the
+// EventListener, and that the Pipelines run by the TriggerTemplates and the Tasks of the Pipelines exist. An error is
+// returned only if a checkout cannot be read.
+func LintTektonDefinitionCheckouts(pipeline *TektonPipeline, checkouts map[string]string) (*TektonLintReport, error) {
+	linter := &tektonLinter{
+		report:    &TektonLintReport{Issues: []TektonLintIssue{}},
+		resources: map[string]map[string]*tektonResource{},
+	}
+	for _, input := range pipeline.Inputs {
+		if err := linter.readInput(&input, checkouts); err != nil {
+			return nil, err
+		}
+	}
+	linter.lintTriggers(pipeline)
+	linter.lintPipelines()
+	return linter.report, nil
+}
+
+// tektonResource is the subset of a Tekton resource read by the linter.
+type tektonResource struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		// EventListener
+		Triggers []tektonEventListenerTrigger `yaml:"triggers"`
+
+		// TriggerTemplate, TriggerBinding
+		Params            []tektonParam            `yaml:"params"`
+		ResourceTemplates []tektonResourceTemplate `yaml:"resourcetemplates"`
+
+		// Pipeline
+		Tasks   []tektonPipelineTask `yaml:"tasks"`
+		Finally []tektonPipelineTask `yaml:"finally"`
+	} `yaml:"spec"`
+
+	file string
+}
+
+type tektonEventListenerTrigger struct {
+	Name     string      `yaml:"name"`
+	Bindings []tektonRef `yaml:"bindings"`
+	Template *tektonRef  `yaml:"template"`
+}
+
+// tektonRef is a reference to a resource by ref or name, bindings may also embed a param with a name and a value.
+type tektonRef struct {
+	Ref    string      `yaml:"ref"`
+	Name   string      `yaml:"name"`
+	Kind   string      `yaml:"kind"`
+	Bundle string      `yaml:"bundle"`
+	Value  interface{} `yaml:"value"`
+}
+
+// target returns the name of the referenced resource.
+func (ref *tektonRef) target() string {
+	if ref.Ref != "" {
+		return ref.Ref
+	}
+	return ref.Name
+}
+
+type tektonParam struct {
+	Name    string      `yaml:"name"`
+	Default interface{} `yaml:"default"`
+}
+
+type tektonResourceTemplate struct {
+	Kind string `yaml:"kind"`
+	Spec struct {
+		PipelineRef *tektonRef `yaml:"pipelineRef"`
+		TaskRef     *tektonRef `yaml:"taskRef"`
+	} `yaml:"spec"`
+}
+
+type tektonPipelineTask struct {
+	Name    string     `yaml:"name"`
+	TaskRef *tektonRef `yaml:"taskRef"`
+}
+
+// tektonLinter collects the Tekton resources of a definition, keyed by kind and name, and the issues found.
+type tektonLinter struct {
+	report    *TektonLintReport
+	resources map[string]map[string]*tektonResource
+}
+
+func (linter *tektonLinter) addIssue(kind string, file string, resource string, format string, args ...interface{}) {
+	linter.report.Issues = append(linter.report.Issues, TektonLintIssue{
+		Kind:     kind,
+		File:     file,
+		Resource: resource,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// get returns the resource of the kind with the name, or nil if the definition has no such resource.
+func (linter *tektonLinter) get(kind string, name string) *tektonResource {
+	return linter.resources[kind][name]
+}
+
+// readInput reads the YAML files found under the path of a definition input.
+func (linter *tektonLinter) readInput(input *TektonPipelineInput, checkouts map[string]string) error {
+	path := "."
+	if input.ScmSource != nil && stringValue(input.ScmSource.Path) != "" {
+		path = *input.ScmSource.Path
+	}
+	checkoutDir, ok := checkouts[stringValue(input.ServiceInstanceID)]
+	if !ok {
+		linter.addIssue(TektonLintIssueKindMissingCheckoutConst, "", "", "no checkout of input %s, the resources at %s are not linted", stringValue(input.ServiceInstanceID), path)
+		return nil
+	}
+
+	root := filepath.Join(checkoutDir, filepath.FromSlash(path))
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		linter.addIssue(TektonLintIssueKindMissingPathConst, "", "", "path %s of input %s not found in %s", path, stringValue(input.ServiceInstanceID), checkoutDir)
+		return nil
+	}
+	return filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		extension := strings.ToLower(filepath.Ext(file))
+		if info.IsDir() || (extension != ".yaml" && extension != ".yml") {
+			return nil
+		}
+		relative, relErr := filepath.Rel(checkoutDir, file)
+		if relErr != nil {
+			relative = file
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		linter.readFile(filepath.ToSlash(relative), data)
+		return nil
+	})
+}
+
+// readFile reads the Tekton resources of the documents of a YAML file.
+func (linter *tektonLinter) readFile(file string, data []byte) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		resource := &tektonResource{file: file}
+		err := decoder.Decode(resource)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if _, ok := err.(*yaml.TypeError); ok && !isLintedTektonKind(resource.Kind) {
+				continue
+			}
+			linter.addIssue(TektonLintIssueKindInvalidYAMLConst, file, "", "%s", err.Error())
+			if _, ok := err.(*yaml.TypeError); ok {
+				continue
+			}
+			return
+		}
+		if !isLintedTektonKind(resource.Kind) {
+			continue
+		}
+		name := resource.Metadata.Name
+		if linter.resources[resource.Kind] == nil {
+			linter.resources[resource.Kind] = map[string]*tektonResource{}
+		}
+		if existing := linter.resources[resource.Kind][name]; existing != nil {
+			linter.addIssue(TektonLintIssueKindDuplicateResourceConst, file, resource.Kind+"/"+name, "already defined in %s", existing.file)
+			continue
+		}
+		linter.resources[resource.Kind][name] = resource
+	}
+}
+
+// isLintedTektonKind returns true if resources of the kind are read by the linter.
+func isLintedTektonKind(kind string) bool {
+	switch kind {
+	case tektonKindEventListener, tektonKindTriggerTemplate, tektonKindTriggerBinding, tektonKindPipeline, tektonKindTask:
+		return true
+	}
+	return false
+}
+
+// lintTriggers verifies the EventListeners referenced by the triggers of the pipeline, each one once.
+func (linter *tektonLinter) lintTriggers(pipeline *TektonPipeline) {
+	properties := map[string]bool{}
+	for _, property := range pipeline.EnvProperties {
+		properties[stringValue(property.Name)] = true
+	}
+
+	linted := map[string]bool{}
+	for _, trigger := range pipeline.Triggers {
+		name := stringValue(trigger.EventListener)
+		if linted[name] {
+			continue
+		}
+		linted[name] = true
+		eventListener := linter.get(tektonKindEventListener, name)
+		if eventListener == nil {
+			linter.addIssue(TektonLintIssueKindMissingEventListenerConst, "", "", "EventListener %s of trigger %s not found", name, triggerName(&trigger))
+			continue
+		}
+		linter.lintEventListener(eventListener, properties)
+	}
+}
+
+// lintEventListener verifies the templates and bindings of the triggers of an EventListener and that the params of
+// the templates are provided.
+func (linter *tektonLinter) lintEventListener(eventListener *tektonResource, properties map[string]bool) {
+	resourceName := tektonKindEventListener + "/" + eventListener.Metadata.Name
+	for _, elTrigger := range eventListener.Spec.Triggers {
+		provided := map[string]bool{}
+		for name := range properties {
+			provided[name] = true
+		}
+		for _, binding := range elTrigger.Bindings {
+			if binding.Ref == "" && binding.Value != nil {
+				provided[binding.Name] = true
+				continue
+			}
+			if binding.Kind != "" && binding.Kind != tektonKindTriggerBinding {
+				continue
+			}
+			triggerBinding := linter.get(tektonKindTriggerBinding, binding.target())
+			if triggerBinding == nil {
+				linter.addIssue(TektonLintIssueKindMissingTriggerBindingConst, eventListener.file, resourceName, "TriggerBinding %s not found", binding.target())
+				continue
+			}
+			for _, param := range triggerBinding.Spec.Params {
+				provided[param.Name] = true
+			}
+		}
+
+		if elTrigger.Template == nil {
+			continue
+		}
+		template := linter.get(tektonKindTriggerTemplate, elTrigger.Template.target())
+		if template == nil {
+			linter.addIssue(TektonLintIssueKindMissingTriggerTemplateConst, eventListener.file, resourceName, "TriggerTemplate %s not found", elTrigger.Template.target())
+			continue
+		}
+		for _, param := range template.Spec.Params {
+			if param.Default == nil && !provided[param.Name] {
+				linter.addIssue(TektonLintIssueKindUnsatisfiedParamConst, template.file, tektonKindTriggerTemplate+"/"+template.Metadata.Name,
+					"param %s has no default and is not provided by an environment property or a binding of %s", param.Name, resourceName)
+			}
+		}
+	}
+}
+
+// lintPipelines verifies the Pipelines and Tasks run by the TriggerTemplates and the Tasks of the Pipelines.
+func (linter *tektonLinter) lintPipelines() {
+	for _, name := range sortedTektonResourceNames(linter.resources[tektonKindTriggerTemplate]) {
+		template := linter.resources[tektonKindTriggerTemplate][name]
+		resourceName := tektonKindTriggerTemplate + "/" + name
+		for _, resourceTemplate := range template.Spec.ResourceTemplates {
+			if ref := resourceTemplate.Spec.PipelineRef; ref != nil && ref.Bundle == "" && linter.get(tektonKindPipeline, ref.target()) == nil {
+				linter.addIssue(TektonLintIssueKindMissingPipelineConst, template.file, resourceName, "Pipeline %s not found", ref.target())
+			}
+			linter.lintTaskRef(resourceTemplate.Spec.TaskRef, template.file, resourceName)
+		}
+	}
+	for _, name := range sortedTektonResourceNames(linter.resources[tektonKindPipeline]) {
+		pipeline := linter.resources[tektonKindPipeline][name]
+		resourceName := tektonKindPipeline + "/" + name
+		for _, task := range append(pipeline.Spec.Tasks, pipeline.Spec.Finally...) {
+			linter.lintTaskRef(task.TaskRef, pipeline.file, resourceName)
+		}
+	}
+}
+
+// lintTaskRef verifies that a reference to a Task resolves, cluster tasks and bundles are not verified.
+func (linter *tektonLinter) lintTaskRef(ref *tektonRef, file string, resourceName string) {
+	if ref == nil || ref.Bundle != "" || (ref.Kind != "" && ref.Kind != tektonKindTask) {
+		return
+	}
+	if linter.get(tektonKindTask, ref.target()) == nil {
+		linter.addIssue(TektonLintIssueKindMissingTaskConst, file, resourceName, "Task %s not found", ref.target())
+	}
+}
+
+// sortedTektonResourceNames returns the names of the resources sorted by file and name.
+func sortedTektonResourceNames(resources map[string]*tektonResource) []string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if resources[names[i]].file != resources[names[j]].file {
+			return resources[names[i]].file < resources[names[j]].file
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
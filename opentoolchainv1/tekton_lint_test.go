@@ -0,0 +1,185 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`LintTektonDefinition`, func() {
+	var checkoutDir string
+	var pipeline *opentoolchainv1.TektonPipeline
+
+	writeFile := func(path string, content string) {
+		file := filepath.Join(checkoutDir, filepath.FromSlash(path))
+		Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(file, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		checkoutDir, err = ioutil.TempDir("", "tekton-lint")
+		Expect(err).To(BeNil())
+
+		writeFile(".tekton/listener.yaml", `apiVersion: triggers.tekton.dev/v1alpha1
+kind: EventListener
+metadata:
+  name: listener
+spec:
+  triggers:
+    - bindings:
+        - ref: binding
+        - name: inline
+          value: x
+      template:
+        ref: template
+---
+apiVersion: triggers.tekton.dev/v1alpha1
+kind: TriggerBinding
+metadata:
+  name: binding
+spec:
+  params:
+    - name: revision
+      value: $(event.after)
+---
+apiVersion: triggers.tekton.dev/v1alpha1
+kind: TriggerTemplate
+metadata:
+  name: template
+spec:
+  params:
+    - name: revision
+    - name: inline
+    - name: apikey
+    - name: branch
+      default: main
+  resourcetemplates:
+    - apiVersion: tekton.dev/v1beta1
+      kind: PipelineRun
+      metadata:
+        generateName: run-
+      spec:
+        pipelineRef:
+          name: pipeline
+`)
+		writeFile(".tekton/pipeline.yml", `apiVersion: tekton.dev/v1beta1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+    - name: build
+      taskRef:
+        name: build
+    - name: shared
+      taskRef:
+        name: git-clone
+        kind: ClusterTask
+  finally:
+    - name: notify
+      taskRef:
+        name: notify
+`)
+		writeFile(".tekton/tasks/build.yaml", `apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+    - name: build
+      image: alpine
+`)
+		writeFile(".tekton/README.md", "not yaml")
+		writeFile("chart/values.yaml", "{{ invalid")
+
+		pipeline = &opentoolchainv1.TektonPipeline{
+			EnvProperties: []opentoolchainv1.EnvProperty{
+				{Name: core.StringPtr("apikey"), Value: core.StringPtr("x"), Type: core.StringPtr("SECURE")},
+			},
+			Inputs: []opentoolchainv1.TektonPipelineInput{
+				{ServiceInstanceID: core.StringPtr("repo1"), ScmSource: &opentoolchainv1.TektonPipelineInputScmSource{Path: core.StringPtr(".tekton")}},
+			},
+			Triggers: []opentoolchainv1.TektonPipelineTrigger{
+				{Name: core.StringPtr("push"), EventListener: core.StringPtr("listener")},
+				{Name: core.StringPtr("manual"), EventListener: core.StringPtr("listener")},
+			},
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(checkoutDir)
+	})
+
+	It(`Invoke LintTektonDefinition successfully`, func() {
+		report, err := opentoolchainv1.LintTektonDefinition(pipeline, checkoutDir)
+		Expect(err).To(BeNil())
+		Expect(report.Issues).To(HaveLen(1))
+		Expect(report.Issues[0].Kind).To(Equal(opentoolchainv1.TektonLintIssueKindMissingTaskConst))
+
+		var output bytes.Buffer
+		Expect(report.Print(&output)).To(Succeed())
+		Expect(output.String()).To(Equal(".tekton/pipeline.yml Pipeline/pipeline: Task notify not found\n"))
+
+		writeFile(".tekton/tasks/notify.yaml", "kind: Task\nmetadata:\n  name: notify\n")
+		report, err = opentoolchainv1.LintTektonDefinition(pipeline, checkoutDir)
+		Expect(err).To(BeNil())
+		Expect(report.IsEmpty()).To(BeTrue())
+		output.Reset()
+		Expect(report.Print(&output)).To(Succeed())
+		Expect(output.String()).To(Equal("No issues\n"))
+	})
+	It(`Invoke LintTektonDefinition with issues`, func() {
+		pipeline.EnvProperties = nil
+		pipeline.Triggers = append(pipeline.Triggers, opentoolchainv1.TektonPipelineTrigger{ID: core.StringPtr("t3"), EventListener: core.StringPtr("missing")})
+		pipeline.Inputs = append(pipeline.Inputs, opentoolchainv1.TektonPipelineInput{
+			ServiceInstanceID: core.StringPtr("repo1"), ScmSource: &opentoolchainv1.TektonPipelineInputScmSource{Path: core.StringPtr("deploy")},
+		})
+		writeFile(".tekton/duplicate.yaml", "kind: Task\nmetadata:\n  name: build\n")
+		writeFile(".tekton/broken.yaml", "kind: Task\nmetadata: [")
+
+		report, err := opentoolchainv1.LintTektonDefinition(pipeline, checkoutDir)
+		Expect(err).To(BeNil())
+		kinds := []string{}
+		for _, issue := range report.Issues {
+			kinds = append(kinds, issue.Kind)
+		}
+		Expect(kinds).To(Equal([]string{
+			opentoolchainv1.TektonLintIssueKindInvalidYAMLConst,
+			opentoolchainv1.TektonLintIssueKindDuplicateResourceConst,
+			opentoolchainv1.TektonLintIssueKindMissingPathConst,
+			opentoolchainv1.TektonLintIssueKindUnsatisfiedParamConst,
+			opentoolchainv1.TektonLintIssueKindMissingEventListenerConst,
+			opentoolchainv1.TektonLintIssueKindMissingTaskConst,
+		}))
+		Expect(report.Issues[1].String()).To(Equal(".tekton/tasks/build.yaml Task/build: already defined in .tekton/duplicate.yaml"))
+		Expect(report.Issues[3].Message).To(Equal("param apikey has no default and is not provided by an environment property or a binding of EventListener/listener"))
+		Expect(report.Issues[4].Message).To(Equal("EventListener missing of trigger t3 not found"))
+
+		// Inputs without checkout are reported
+		report, err = opentoolchainv1.LintTektonDefinitionCheckouts(pipeline, map[string]string{})
+		Expect(err).To(BeNil())
+		Expect(report.Issues[0].Kind).To(Equal(opentoolchainv1.TektonLintIssueKindMissingCheckoutConst))
+	})
+})
@@ -0,0 +1,372 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// CopyTektonPipelineConfigResult : The result of CopyTektonPipelineConfig
+type CopyTektonPipelineConfigResult struct {
+	// Source pipeline the configuration was copied from.
+	Source *TektonPipeline
+
+	// Target pipeline, as returned by PatchTektonPipeline, or before the copy in dry-run mode.
+	Target *TektonPipeline
+
+	// Changes applied to the target pipeline.
+	Diff *TektonPipelineDiff
+
+	// Service instance IDs of the source toolchain mapped to the tool integrations of the target toolchain.
+	ServiceInstanceIDs map[string]string
+
+	// SECURE environment properties of the source pipeline the target pipeline lacks. Their values are masked when
+	// read and cannot be copied, so they must be set on the target pipeline by hand.
+	SecurePropertiesToSet []string
+}
+
+// CopyTektonPipelineConfig : Copy the configuration of a Tekton pipeline to another pipeline
+// The environment properties, inputs, triggers and worker of the source pipeline replace those of the target
+// pipeline. When the pipelines belong to different toolchains, the service instance IDs referenced by inputs and
+// triggers are remapped to the tool integrations of the target toolchain with the same service ID and name; an error is
+// returned if one of them has no unique counterpart. Triggers keep the ID of the target trigger with the same name and
+// inputs drop their shard definition ID, since definitions belong to the source pipeline. The values of SECURE
+// environment properties are masked when read and are never copied: the target keeps its SECURE properties of the
+// same name, and those it lacks are listed in SecurePropertiesToSet instead of being created. The target is patched with
+// its UpdatedAtTimestamp as the expected one, so a *TektonPipelineConflictError is returned if it is updated
+// concurrently.
+func (openToolchain *OpenToolchainV1) CopyTektonPipelineConfig(copyTektonPipelineConfigOptions *CopyTektonPipelineConfigOptions) (result *CopyTektonPipelineConfigResult, response *core.DetailedResponse, err error) {
+	return openToolchain.CopyTektonPipelineConfigWithContext(context.Background(), copyTektonPipelineConfigOptions)
+}
+
+// CopyTektonPipelineConfigWithContext is an alternate form of the CopyTektonPipelineConfig method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) CopyTektonPipelineConfigWithContext(ctx context.Context, copyTektonPipelineConfigOptions *CopyTektonPipelineConfigOptions) (result *CopyTektonPipelineConfigResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(copyTektonPipelineConfigOptions, "copyTektonPipelineConfigOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(copyTektonPipelineConfigOptions, "copyTektonPipelineConfigOptions")
+	if err != nil {
+		return
+	}
+
+	sourceRegion := *copyTektonPipelineConfigOptions.Region
+	targetRegion := sourceRegion
+	if copyTektonPipelineConfigOptions.TargetRegion != nil && *copyTektonPipelineConfigOptions.TargetRegion != "" {
+		targetRegion = *copyTektonPipelineConfigOptions.TargetRegion
+	}
+	headers := copyTektonPipelineConfigOptions.Headers
+
+	getTektonPipelineOptions := openToolchain.NewGetTektonPipelineOptions(*copyTektonPipelineConfigOptions.SourceGUID, sourceRegion)
+	getTektonPipelineOptions.SetHeaders(headers)
+	source, response, err := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+	getTektonPipelineOptions = openToolchain.NewGetTektonPipelineOptions(*copyTektonPipelineConfigOptions.TargetGUID, targetRegion)
+	getTektonPipelineOptions.SetHeaders(headers)
+	target, response, err := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+	if source == nil || target == nil {
+		err = fmt.Errorf("error getting pipelines %s and %s", *copyTektonPipelineConfigOptions.SourceGUID, *copyTektonPipelineConfigOptions.TargetGUID)
+		return
+	}
+
+	result = &CopyTektonPipelineConfigResult{
+		Source:             source,
+		Target:             target,
+		ServiceInstanceIDs: map[string]string{},
+	}
+
+	referenced := referencedServiceInstanceIDs(source)
+	if stringValue(source.ToolchainID) == stringValue(target.ToolchainID) && sourceRegion == targetRegion {
+		for _, instanceID := range referenced {
+			result.ServiceInstanceIDs[instanceID] = instanceID
+		}
+	} else if len(referenced) > 0 {
+		var sourceServices, targetServices []Service
+		sourceServices, response, err = openToolchain.getToolchainServices(ctx, sourceRegion, stringValue(source.ToolchainID), headers)
+		if err != nil {
+			return
+		}
+		targetServices, response, err = openToolchain.getToolchainServices(ctx, targetRegion, stringValue(target.ToolchainID), headers)
+		if err != nil {
+			return
+		}
+		result.ServiceInstanceIDs, err = mapServiceInstanceIDs(referenced, sourceServices, targetServices)
+		if err != nil {
+			return
+		}
+	}
+
+	excludeSecure := copyTektonPipelineConfigOptions.ExcludeSecureProperties != nil && *copyTektonPipelineConfigOptions.ExcludeSecureProperties
+	copied, securePropertiesToSet := copyTektonPipelineConfig(source, target, result.ServiceInstanceIDs, excludeSecure)
+	result.SecurePropertiesToSet = securePropertiesToSet
+	result.Diff = DiffTektonPipelines(target, copied)
+	if copyTektonPipelineConfigOptions.DryRun != nil && *copyTektonPipelineConfigOptions.DryRun {
+		return
+	}
+
+	patchTektonPipelineOptions := openToolchain.NewPatchTektonPipelineOptions(*copyTektonPipelineConfigOptions.TargetGUID, targetRegion)
+	patchTektonPipelineOptions.SetEnvProperties(copied.EnvProperties)
+	patchTektonPipelineOptions.SetInputs(copied.Inputs)
+	patchTektonPipelineOptions.SetTriggers(copied.Triggers)
+	if copied.Worker != nil {
+		patchTektonPipelineOptions.SetWorker(&PatchTektonPipelineParamsWorker{
+			WorkerID:   copied.Worker.WorkerID,
+			WorkerName: copied.Worker.WorkerName,
+			WorkerType: copied.Worker.WorkerType,
+		})
+	}
+	patchTektonPipelineOptions.SetBase(target)
+	patchTektonPipelineOptions.SetHeaders(headers)
+	patched, response, err := openToolchain.PatchTektonPipelineWithContext(ctx, patchTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+	result.Target = patched
+	return
+}
+
+// getToolchainServices returns the tool integrations of a toolchain.
+func (openToolchain *OpenToolchainV1) getToolchainServices(ctx context.Context, region string, guid string, headers map[string]string) ([]Service, *core.DetailedResponse, error) {
+	getToolchainOptions := openToolchain.NewGetToolchainOptions(region, guid)
+	getToolchainOptions.SetIncludes(NewToolchainIncludes().WithServices())
+	getToolchainOptions.SetHeaders(headers)
+	toolchainResponse, response, err := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+	if err != nil {
+		return nil, response, err
+	}
+	if toolchainResponse == nil || len(toolchainResponse.Items) == 0 {
+		return nil, response, fmt.Errorf("toolchain %s not found", guid)
+	}
+	return toolchainResponse.Items[0].Services, response, nil
+}
+
+// referencedServiceInstanceIDs returns the service instance IDs referenced by the inputs and triggers of a pipeline.
+func referencedServiceInstanceIDs(pipeline *TektonPipeline) []string {
+	seen := map[string]bool{}
+	instanceIDs := []string{}
+	add := func(instanceID *string) {
+		if instanceID != nil && *instanceID != "" && !seen[*instanceID] {
+			seen[*instanceID] = true
+			instanceIDs = append(instanceIDs, *instanceID)
+		}
+	}
+	for i := range pipeline.Inputs {
+		add(pipeline.Inputs[i].ServiceInstanceID)
+	}
+	for i := range pipeline.Triggers {
+		add(pipeline.Triggers[i].ServiceInstanceID)
+	}
+	return instanceIDs
+}
+
+// toolName returns the key matching a tool integration across toolchains: its service ID and its name.
+func toolName(service *Service) string {
+	return stringValue(service.ServiceID) + "/" + serviceName(service)
+}
+
+// mapServiceInstanceIDs maps the referenced service instance IDs of the source toolchain to the tool integrations of
+// the target toolchain with the same tool name.
+func mapServiceInstanceIDs(referenced []string, sourceServices []Service, targetServices []Service) (map[string]string, error) {
+	sourceNames := map[string]string{}
+	for i := range sourceServices {
+		sourceNames[stringValue(sourceServices[i].InstanceID)] = toolName(&sourceServices[i])
+	}
+	targetIDs := map[string][]string{}
+	for i := range targetServices {
+		name := toolName(&targetServices[i])
+		targetIDs[name] = append(targetIDs[name], stringValue(targetServices[i].InstanceID))
+	}
+
+	mapping := map[string]string{}
+	unmapped := []string{}
+	for _, instanceID := range referenced {
+		name, ok := sourceNames[instanceID]
+		if !ok {
+			unmapped = append(unmapped, instanceID)
+			continue
+		}
+		if candidates := targetIDs[name]; len(candidates) == 1 {
+			mapping[instanceID] = candidates[0]
+		} else {
+			unmapped = append(unmapped, fmt.Sprintf("%s (%s)", instanceID, name))
+		}
+	}
+	if len(unmapped) > 0 {
+		sort.Strings(unmapped)
+		return mapping, fmt.Errorf("no unique tool integration in the target toolchain for %s", strings.Join(unmapped, ", "))
+	}
+	return mapping, nil
+}
+
+// copyTektonPipelineConfig returns the target pipeline with the configuration of the source pipeline, and the names
+// of the SECURE properties of the source the target lacks.
+func copyTektonPipelineConfig(source *TektonPipeline, target *TektonPipeline, serviceInstanceIDs map[string]string, excludeSecure bool) (*TektonPipeline, []string) {
+	remap := func(instanceID *string) *string {
+		if instanceID == nil {
+			return nil
+		}
+		if mapped, ok := serviceInstanceIDs[*instanceID]; ok {
+			return core.StringPtr(mapped)
+		}
+		return instanceID
+	}
+	isSecure := func(envProperty *EnvProperty) bool {
+		return strings.EqualFold(stringValue(envProperty.Type), EnvPropertyTypeSecureConst)
+	}
+
+	copied := *target
+
+	targetSecure := map[string]*EnvProperty{}
+	for i := range target.EnvProperties {
+		if isSecure(&target.EnvProperties[i]) {
+			targetSecure[stringValue(target.EnvProperties[i].Name)] = &target.EnvProperties[i]
+		}
+	}
+
+	copied.EnvProperties = []EnvProperty{}
+	securePropertiesToSet := []string{}
+	for i := range source.EnvProperties {
+		envProperty := &source.EnvProperties[i]
+		switch {
+		case !isSecure(envProperty):
+			copied.EnvProperties = append(copied.EnvProperties, *envProperty)
+		case excludeSecure:
+		case targetSecure[stringValue(envProperty.Name)] != nil:
+			// The masked value of the source is not copied over the value of the target
+			copied.EnvProperties = append(copied.EnvProperties, *targetSecure[stringValue(envProperty.Name)])
+		default:
+			securePropertiesToSet = append(securePropertiesToSet, stringValue(envProperty.Name))
+		}
+	}
+	if excludeSecure {
+		// The secure properties of the target are kept
+		for i := range target.EnvProperties {
+			if isSecure(&target.EnvProperties[i]) {
+				copied.EnvProperties = append(copied.EnvProperties, target.EnvProperties[i])
+			}
+		}
+	}
+
+	copied.Inputs = []TektonPipelineInput{}
+	for _, input := range source.Inputs {
+		input.ServiceInstanceID = remap(input.ServiceInstanceID)
+		input.ShardDefinitionID = nil
+		copied.Inputs = append(copied.Inputs, input)
+	}
+
+	targetTriggerIDs := map[string]*string{}
+	for i := range target.Triggers {
+		targetTriggerIDs[stringValue(target.Triggers[i].Name)] = target.Triggers[i].ID
+	}
+	copied.Triggers = []TektonPipelineTrigger{}
+	for _, trigger := range source.Triggers {
+		trigger.ID = targetTriggerIDs[stringValue(trigger.Name)]
+		trigger.ServiceInstanceID = remap(trigger.ServiceInstanceID)
+		copied.Triggers = append(copied.Triggers, trigger)
+	}
+
+	if source.Worker != nil {
+		copied.Worker = source.Worker
+	}
+	return &copied, securePropertiesToSet
+}
+
+// CopyTektonPipelineConfigOptions : The CopyTektonPipelineConfig options.
+type CopyTektonPipelineConfigOptions struct {
+	// GUID of the pipeline to copy the configuration from.
+	SourceGUID *string `validate:"required,ne="`
+
+	// GUID of the pipeline to copy the configuration to.
+	TargetGUID *string `validate:"required,ne="`
+
+	// Region of the source pipeline.
+	Region *string `validate:"required,ne="`
+
+	// Region of the target pipeline. Defaults to Region.
+	TargetRegion *string
+
+	// Ignore the SECURE environment properties of the source pipeline and keep all those of the target pipeline. By
+	// default the target only keeps the SECURE properties the source also has.
+	ExcludeSecureProperties *bool
+
+	// Return the changes without patching the target pipeline.
+	DryRun *bool
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewCopyTektonPipelineConfigOptions : Instantiate CopyTektonPipelineConfigOptions
+func (*OpenToolchainV1) NewCopyTektonPipelineConfigOptions(sourceGUID string, targetGUID string, region string) *CopyTektonPipelineConfigOptions {
+	return &CopyTektonPipelineConfigOptions{
+		SourceGUID: core.StringPtr(sourceGUID),
+		TargetGUID: core.StringPtr(targetGUID),
+		Region:     core.StringPtr(region),
+	}
+}
+
+// SetSourceGUID : Allow user to set SourceGUID
+func (options *CopyTektonPipelineConfigOptions) SetSourceGUID(sourceGUID string) *CopyTektonPipelineConfigOptions {
+	options.SourceGUID = core.StringPtr(sourceGUID)
+	return options
+}
+
+// SetTargetGUID : Allow user to set TargetGUID
+func (options *CopyTektonPipelineConfigOptions) SetTargetGUID(targetGUID string) *CopyTektonPipelineConfigOptions {
+	options.TargetGUID = core.StringPtr(targetGUID)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *CopyTektonPipelineConfigOptions) SetRegion(region string) *CopyTektonPipelineConfigOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetTargetRegion : Allow user to set TargetRegion
+func (options *CopyTektonPipelineConfigOptions) SetTargetRegion(targetRegion string) *CopyTektonPipelineConfigOptions {
+	options.TargetRegion = core.StringPtr(targetRegion)
+	return options
+}
+
+// SetExcludeSecureProperties : Allow user to set ExcludeSecureProperties
+func (options *CopyTektonPipelineConfigOptions) SetExcludeSecureProperties(excludeSecureProperties bool) *CopyTektonPipelineConfigOptions {
+	options.ExcludeSecureProperties = core.BoolPtr(excludeSecureProperties)
+	return options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (options *CopyTektonPipelineConfigOptions) SetDryRun(dryRun bool) *CopyTektonPipelineConfigOptions {
+	options.DryRun = core.BoolPtr(dryRun)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *CopyTektonPipelineConfigOptions) SetHeaders(param map[string]string) *CopyTektonPipelineConfigOptions {
+	options.Headers = param
+	return options
+}
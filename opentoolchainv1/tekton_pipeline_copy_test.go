@@ -0,0 +1,178 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`CopyTektonPipelineConfig`, func() {
+	var testServer *httptest.Server
+	var patch map[string]interface{}
+	var stagingServices string
+
+	BeforeEach(func() {
+		patch = nil
+		stagingServices = `{"service_id": "githubconsolidated", "instance_id": "stage-repo", "parameters": {"name": "app"}},
+			{"service_id": "githubconsolidated", "instance_id": "stage-tekton", "parameters": {"name": "tekton-catalog"}},
+			{"service_id": "slack", "instance_id": "stage-slack", "parameters": {"name": "app"}}`
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/tekton-pipelines/prod"):
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "prod", "name": "prod", "toolchainId": "tc-prod", "updated_at_timestamp": 1,
+					"worker": {"workerId": "private", "workerName": "prod worker", "workerType": "private"},
+					"envProperties": [{"name": "replicas", "value": "3", "type": "TEXT"}, {"name": "apikey", "value": "********", "type": "SECURE"}, {"name": "token", "value": "********", "type": "SECURE"}],
+					"inputs": [{"type": "scm", "serviceInstanceId": "prod-repo", "shardDefinitionId": "prod-shard", "scmSource": {"path": ".tekton", "url": "https://github.com/org/app", "branch": "main"}},
+						{"type": "scm", "serviceInstanceId": "prod-tekton", "shardDefinitionId": "prod-shard2", "scmSource": {"path": "pipeline", "url": "https://github.com/org/tekton-catalog", "branch": "main"}}],
+					"triggers": [{"id": "prod-t1", "name": "git push", "eventListener": "listener", "type": "scm", "serviceInstanceId": "prod-repo", "events": {"push": true}},
+						{"id": "prod-t2", "name": "nightly", "eventListener": "listener", "type": "timer"}]}`)
+			case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/tekton-pipelines/stage"):
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "stage", "name": "stage", "toolchainId": "tc-stage", "updated_at_timestamp": 7,
+					"envProperties": [{"name": "replicas", "value": "1", "type": "TEXT"}, {"name": "apikey", "value": "stage-key", "type": "SECURE"}],
+					"triggers": [{"id": "stage-t1", "name": "git push", "eventListener": "listener", "type": "scm", "serviceInstanceId": "stage-repo", "events": {"push": true}}]}`)
+			case req.Method == "PATCH" && strings.HasSuffix(req.URL.Path, "/tekton-pipelines/stage/config"):
+				Expect(json.NewDecoder(req.Body).Decode(&patch)).To(Succeed())
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "stage", "name": "stage", "toolchainId": "tc-stage", "updated_at_timestamp": 8, "envProperties": []}`)
+			case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/toolchains/tc-prod"):
+				Expect(req.URL.Query().Get("include")).To(Equal("services"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc-prod", "name": "prod", "services": [
+					{"service_id": "githubconsolidated", "instance_id": "prod-repo", "parameters": {"name": "app"}},
+					{"service_id": "githubconsolidated", "instance_id": "prod-tekton", "toolchain_binding": {"name": "tekton-catalog"}}]}]}`)
+			case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/toolchains/tc-stage"):
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc-stage", "name": "stage", "services": [%s]}]}`, stagingServices)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke CopyTektonPipelineConfig successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		copyTektonPipelineConfigOptionsModel := openToolchainService.NewCopyTektonPipelineConfigOptions("prod", "stage", "us-south")
+		copyTektonPipelineConfigOptionsModel.SetExcludeSecureProperties(true)
+		result, response, err := openToolchainService.CopyTektonPipelineConfig(copyTektonPipelineConfigOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(result.ServiceInstanceIDs).To(Equal(map[string]string{"prod-repo": "stage-repo", "prod-tekton": "stage-tekton"}))
+		Expect(*result.Source.ID).To(Equal("prod"))
+		Expect(*result.Target.UpdatedAtTimestamp).To(Equal(float64(8)))
+		Expect(result.SecurePropertiesToSet).To(BeEmpty())
+
+		Expect(patch["envProperties"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "replicas", "value": "3", "type": "TEXT"},
+			map[string]interface{}{"name": "apikey", "value": "stage-key", "type": "SECURE"},
+		}))
+		inputs := patch["inputs"].([]interface{})
+		Expect(inputs).To(HaveLen(2))
+		Expect(inputs[0].(map[string]interface{})["serviceInstanceId"]).To(Equal("stage-repo"))
+		Expect(inputs[0].(map[string]interface{})).ToNot(HaveKey("shardDefinitionId"))
+		Expect(inputs[1].(map[string]interface{})["serviceInstanceId"]).To(Equal("stage-tekton"))
+		triggers := patch["triggers"].([]interface{})
+		Expect(triggers).To(HaveLen(2))
+		Expect(triggers[0].(map[string]interface{})["id"]).To(Equal("stage-t1"))
+		Expect(triggers[0].(map[string]interface{})["serviceInstanceId"]).To(Equal("stage-repo"))
+		Expect(triggers[1].(map[string]interface{})).ToNot(HaveKey("id"))
+		Expect(patch["worker"]).To(Equal(map[string]interface{}{"workerId": "private", "workerName": "prod worker", "workerType": "private"}))
+		Expect(patch).ToNot(HaveKey("pipelineDefinitionId"))
+
+		paths := []string{}
+		for i := range result.Diff.Changes {
+			paths = append(paths, result.Diff.Changes[i].Path())
+		}
+		Expect(paths).To(ContainElement("envProperties[replicas].value"))
+		Expect(paths).ToNot(ContainElement("envProperties[apikey].value"))
+		Expect(paths).To(ContainElement("triggers[nightly]"))
+	})
+	It(`Invoke CopyTektonPipelineConfig in dry-run mode`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		copyTektonPipelineConfigOptionsModel := openToolchainService.NewCopyTektonPipelineConfigOptions("prod", "stage", "us-south")
+		copyTektonPipelineConfigOptionsModel.SetDryRun(true)
+		result, _, err := openToolchainService.CopyTektonPipelineConfig(copyTektonPipelineConfigOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(patch).To(BeNil())
+		Expect(*result.Target.UpdatedAtTimestamp).To(Equal(float64(7)))
+
+		// The masked values of the source are neither copied over the target nor created
+		Expect(result.SecurePropertiesToSet).To(Equal([]string{"token"}))
+		for i := range result.Diff.Changes {
+			Expect(result.Diff.Changes[i].Key).ToNot(Equal("apikey"))
+			Expect(result.Diff.Changes[i].Key).ToNot(Equal("token"))
+		}
+	})
+	It(`Invoke CopyTektonPipelineConfig with SECURE properties`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, _, err := openToolchainService.CopyTektonPipelineConfig(openToolchainService.NewCopyTektonPipelineConfigOptions("prod", "stage", "us-south"))
+		Expect(err).To(BeNil())
+		Expect(result.SecurePropertiesToSet).To(Equal([]string{"token"}))
+		Expect(patch["envProperties"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "replicas", "value": "3", "type": "TEXT"},
+			map[string]interface{}{"name": "apikey", "value": "stage-key", "type": "SECURE"},
+		}))
+	})
+	It(`Invoke CopyTektonPipelineConfig with error: tool integration missing from the target toolchain`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		stagingServices = `{"service_id": "githubconsolidated", "instance_id": "stage-repo", "parameters": {"name": "app"}}`
+		_, _, err := openToolchainService.CopyTektonPipelineConfig(openToolchainService.NewCopyTektonPipelineConfigOptions("prod", "stage", "us-south"))
+		Expect(err).To(MatchError("no unique tool integration in the target toolchain for prod-tekton (githubconsolidated/tekton-catalog)"))
+		Expect(patch).To(BeNil())
+
+		result, response, err := openToolchainService.CopyTektonPipelineConfig(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+	})
+})
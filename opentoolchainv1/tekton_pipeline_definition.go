@@ -0,0 +1,199 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// ListTektonPipelineDefinitions : List the definitions of a tekton pipeline
+// The definition inputs of the pipeline are listed along with the shard repository the definition was last read from.
+func (openToolchain *OpenToolchainV1) ListTektonPipelineDefinitions(listTektonPipelineDefinitionsOptions *ListTektonPipelineDefinitionsOptions) (result *TektonPipelineDefinitionList, response *core.DetailedResponse, err error) {
+	return openToolchain.ListTektonPipelineDefinitionsWithContext(context.Background(), listTektonPipelineDefinitionsOptions)
+}
+
+// ListTektonPipelineDefinitionsWithContext is an alternate form of the ListTektonPipelineDefinitions method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) ListTektonPipelineDefinitionsWithContext(ctx context.Context, listTektonPipelineDefinitionsOptions *ListTektonPipelineDefinitionsOptions) (result *TektonPipelineDefinitionList, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listTektonPipelineDefinitionsOptions, "listTektonPipelineDefinitionsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(listTektonPipelineDefinitionsOptions, "listTektonPipelineDefinitionsOptions")
+	if err != nil {
+		return
+	}
+
+	guid := *listTektonPipelineDefinitionsOptions.GUID
+	getTektonPipelineOptions := openToolchain.NewGetTektonPipelineOptions(guid, *listTektonPipelineDefinitionsOptions.Region)
+	getTektonPipelineOptions.SetHeaders(listTektonPipelineDefinitionsOptions.Headers)
+	pipeline, response, err := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+	if err != nil {
+		return
+	}
+
+	getTektonPipelineDefinitionOptions := openToolchain.NewGetTektonPipelineDefinitionOptions(guid, *listTektonPipelineDefinitionsOptions.Region, *listTektonPipelineDefinitionsOptions.EnvID)
+	getTektonPipelineDefinitionOptions.SetHeaders(listTektonPipelineDefinitionsOptions.Headers)
+	definition, response, err := openToolchain.GetTektonPipelineDefinitionWithContext(ctx, getTektonPipelineDefinitionOptions)
+	if err != nil {
+		return
+	}
+
+	result = buildTektonPipelineDefinitionList(guid, pipeline, definition)
+	return
+}
+
+// buildTektonPipelineDefinitionList matches the definition inputs of a pipeline to the shard repositories of its
+// definition through their shard definition ID, shard repositories without input are listed last.
+func buildTektonPipelineDefinitionList(guid string, pipeline *TektonPipeline, definition *GetTektonPipelineDefinitionResponse) *TektonPipelineDefinitionList {
+	list := &TektonPipelineDefinitionList{
+		PipelineID: guid,
+		Definition: definition,
+		Items:      []TektonPipelineDefinition{},
+	}
+
+	shardRepos := map[string]*ShardRepo{}
+	if definition != nil {
+		for i := range definition.ShardRepos {
+			shardRepo := &definition.ShardRepos[i]
+			shardRepos[stringValue(shardRepo.ShardDefinitionID)] = shardRepo
+		}
+	}
+
+	listed := map[string]bool{}
+	if pipeline != nil {
+		for i := range pipeline.Inputs {
+			input := &pipeline.Inputs[i]
+			id := stringValue(input.ShardDefinitionID)
+			list.Items = append(list.Items, TektonPipelineDefinition{
+				ID:        id,
+				Input:     input,
+				ShardRepo: shardRepos[id],
+			})
+			listed[id] = true
+		}
+	}
+	if definition != nil {
+		for i := range definition.ShardRepos {
+			shardRepo := &definition.ShardRepos[i]
+			if id := stringValue(shardRepo.ShardDefinitionID); !listed[id] {
+				list.Items = append(list.Items, TektonPipelineDefinition{ID: id, ShardRepo: shardRepo})
+			}
+		}
+	}
+	return list
+}
+
+// SetBranch : Allow user to set the branch of ScmSource
+func (options *UpdateTektonPipelineDefinitionOptions) SetBranch(branch string) *UpdateTektonPipelineDefinitionOptions {
+	if options.ScmSource == nil {
+		options.ScmSource = &CreateTektonPipelineDefinitionParamsInputsItemScmSource{}
+	}
+	options.ScmSource.Branch = core.StringPtr(branch)
+	return options
+}
+
+// SetPath : Allow user to set the path of ScmSource
+func (options *UpdateTektonPipelineDefinitionOptions) SetPath(path string) *UpdateTektonPipelineDefinitionOptions {
+	if options.ScmSource == nil {
+		options.ScmSource = &CreateTektonPipelineDefinitionParamsInputsItemScmSource{}
+	}
+	options.ScmSource.Path = core.StringPtr(path)
+	return options
+}
+
+// ListTektonPipelineDefinitionsOptions : The ListTektonPipelineDefinitions options.
+type ListTektonPipelineDefinitionsOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewListTektonPipelineDefinitionsOptions : Instantiate ListTektonPipelineDefinitionsOptions
+func (*OpenToolchainV1) NewListTektonPipelineDefinitionsOptions(guid string, region string, envID string) *ListTektonPipelineDefinitionsOptions {
+	return &ListTektonPipelineDefinitionsOptions{
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		EnvID:  core.StringPtr(envID),
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *ListTektonPipelineDefinitionsOptions) SetGUID(guid string) *ListTektonPipelineDefinitionsOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *ListTektonPipelineDefinitionsOptions) SetRegion(region string) *ListTektonPipelineDefinitionsOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *ListTektonPipelineDefinitionsOptions) SetEnvID(envID string) *ListTektonPipelineDefinitionsOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ListTektonPipelineDefinitionsOptions) SetHeaders(param map[string]string) *ListTektonPipelineDefinitionsOptions {
+	options.Headers = param
+	return options
+}
+
+// TektonPipelineDefinitionList : Definitions of a tekton pipeline
+type TektonPipelineDefinitionList struct {
+	PipelineID string
+
+	// Definition of the pipeline as returned by GetTektonPipelineDefinition.
+	Definition *GetTektonPipelineDefinitionResponse
+
+	// Definitions in the order of the pipeline inputs.
+	Items []TektonPipelineDefinition
+}
+
+// Get returns the definition with the ID, or nil if the pipeline has no such definition
+func (list *TektonPipelineDefinitionList) Get(id string) *TektonPipelineDefinition {
+	for i := range list.Items {
+		if list.Items[i].ID == id {
+			return &list.Items[i]
+		}
+	}
+	return nil
+}
+
+// TektonPipelineDefinition : Definition input of a tekton pipeline
+type TektonPipelineDefinition struct {
+	// Shard definition ID identifying the definition.
+	ID string
+
+	// Input of the pipeline, nil if the definition has no matching input.
+	Input *TektonPipelineInput
+
+	// Repository the definition was last read from, nil if it was not read yet.
+	ShardRepo *ShardRepo
+}
@@ -0,0 +1,135 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`TektonPipelineDefinition`, func() {
+	var testServer *httptest.Server
+	var deleted bool
+
+	BeforeEach(func() {
+		deleted = false
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "PUT" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition/def1":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				Expect(body).To(Equal(map[string]interface{}{
+					"scmSource": map[string]interface{}{"branch": "main", "path": ".tekton/ci"},
+				}))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"definition": {"id": "def1", "pipelineId": "pl1", "branch": "main", "path": ".tekton/ci"},
+					"inputs": [{"type": "scm", "serviceInstanceId": "repo1", "shardDefinitionId": "def1", "scmSource": {"branch": "main", "path": ".tekton/ci"}}]}`)
+			case req.Method == "DELETE" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition/def2":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				deleted = true
+				res.WriteHeader(204)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl1", "name": "ci", "toolchainId": "tc1", "envProperties": [], "inputs": [
+					{"type": "scm", "serviceInstanceId": "repo1", "shardDefinitionId": "def1", "scmSource": {"branch": "master", "path": ".tekton"}},
+					{"type": "scm", "serviceInstanceId": "repo2", "shardDefinitionId": "def2", "scmSource": {"branch": "main", "path": "tasks"}}]}`)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "defs", "pipelineId": "pl1", "shardRepos": [
+					{"shardDefinitionId": "def1", "repoUrl": "https://github.com/org/app", "path": ".tekton", "sha": "abc"},
+					{"shardDefinitionId": "def3", "repoUrl": "https://github.com/org/old", "path": "old", "sha": "def"}]}`)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke UpdateTektonPipelineDefinition and DeleteTektonPipelineDefinition successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		updateTektonPipelineDefinitionOptionsModel := openToolchainService.NewUpdateTektonPipelineDefinitionOptions("pl1", "def1", "us-south", "ibm:yp:us-south")
+		updateTektonPipelineDefinitionOptionsModel.SetBranch("main").SetPath(".tekton/ci")
+		result, response, err := openToolchainService.UpdateTektonPipelineDefinition(updateTektonPipelineDefinitionOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(*result.Definition.Branch).To(Equal("main"))
+		Expect(*result.Inputs[0].ScmSource.Path).To(Equal(".tekton/ci"))
+
+		response, err = openToolchainService.DeleteTektonPipelineDefinition(openToolchainService.NewDeleteTektonPipelineDefinitionOptions("pl1", "def2", "us-south", "ibm:yp:us-south"))
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(204))
+		Expect(deleted).To(BeTrue())
+	})
+	It(`Invoke ListTektonPipelineDefinitions successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, _, err := openToolchainService.ListTektonPipelineDefinitions(openToolchainService.NewListTektonPipelineDefinitionsOptions("pl1", "us-south", "ibm:yp:us-south"))
+		Expect(err).To(BeNil())
+		Expect(result.PipelineID).To(Equal("pl1"))
+		Expect(result.Items).To(HaveLen(3))
+		Expect(*result.Get("def1").Input.ServiceInstanceID).To(Equal("repo1"))
+		Expect(*result.Get("def1").ShardRepo.Sha).To(Equal("abc"))
+		// The definition of the second input was not read yet
+		Expect(result.Get("def2").ShardRepo).To(BeNil())
+		// Shard repositories without input are listed last
+		Expect(result.Items[2].ID).To(Equal("def3"))
+		Expect(result.Items[2].Input).To(BeNil())
+		Expect(result.Get("def4")).To(BeNil())
+	})
+	It(`Invoke TektonPipelineDefinition operations with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, response, err := openToolchainService.UpdateTektonPipelineDefinition(openToolchainService.NewUpdateTektonPipelineDefinitionOptions("pl1", "", "us-south", "ibm:yp:us-south"))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(result).To(BeNil())
+		response, err = openToolchainService.DeleteTektonPipelineDefinition(nil)
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		list, response, err := openToolchainService.ListTektonPipelineDefinitions(openToolchainService.NewListTektonPipelineDefinitionsOptions("pl1", "", "ibm:yp:us-south"))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(list).To(BeNil())
+	})
+})
@@ -0,0 +1,331 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Constants associated with the TektonPipelineChange.Section property.
+const (
+	TektonPipelineChangeSectionEnvPropertiesConst        = "envProperties"
+	TektonPipelineChangeSectionInputsConst               = "inputs"
+	TektonPipelineChangeSectionTriggersConst             = "triggers"
+	TektonPipelineChangeSectionWorkerConst               = "worker"
+	TektonPipelineChangeSectionPipelineDefinitionIDConst = "pipelineDefinitionId"
+)
+
+// Constants associated with the TektonPipelineChange.Kind property.
+const (
+	TektonPipelineChangeKindAddedConst    = "added"
+	TektonPipelineChangeKindRemovedConst  = "removed"
+	TektonPipelineChangeKindModifiedConst = "modified"
+)
+
+// TektonPipelineDiff : Changes between two Tekton pipeline configurations
+type TektonPipelineDiff struct {
+	// Changes ordered by section, then in the order the items are listed in the pipelines.
+	Changes []TektonPipelineChange `json:"changes"`
+}
+
+// TektonPipelineChange : A single change between two Tekton pipeline configurations
+type TektonPipelineChange struct {
+	// One of the TektonPipelineChangeSection constants.
+	Section string `json:"section"`
+
+	// One of the TektonPipelineChangeKind constants.
+	Kind string `json:"kind"`
+
+	// Identifies the item within the section: the property name, the input service instance ID and path, or the
+	// trigger name. Empty for worker and pipelineDefinitionId.
+	Key string `json:"key,omitempty"`
+
+	// Name of the modified field. Empty for added and removed items.
+	Field string `json:"field,omitempty"`
+
+	// Value before and after the change. Added and removed items are summarized as a list of field=value pairs.
+	// Values of SECURE properties are replaced by RedactedValue.
+	Old string `json:"old,omitempty"`
+
+	New string `json:"new,omitempty"`
+}
+
+// diffField is a named field of a pipeline configuration item, in the order it is compared.
+type diffField struct {
+	name  string
+	value string
+
+	// Whether the value is rendered as RedactedValue.
+	secret bool
+}
+
+// display returns the value of the field as it is shown in a change.
+func (field diffField) display() string {
+	if field.secret && field.value != "" {
+		return RedactedValue
+	}
+	return field.value
+}
+
+// diffItem is an item of a pipeline configuration section identified by a key.
+type diffItem struct {
+	key    string
+	fields []diffField
+}
+
+// DiffTektonPipelines : Compare the configuration of two Tekton pipelines
+// Environment properties are matched by name, inputs by service instance ID and path and triggers by name, falling
+// back to their ID. Server-managed fields such as Created, UpdatedAt and RunsURL are ignored, as well as the
+// identity of the pipelines, so the configuration of different pipelines can be compared. A nil pipeline is treated
+// as an empty configuration.
+func DiffTektonPipelines(a, b *TektonPipeline) *TektonPipelineDiff {
+	if a == nil {
+		a = &TektonPipeline{}
+	}
+	if b == nil {
+		b = &TektonPipeline{}
+	}
+
+	diff := &TektonPipelineDiff{Changes: []TektonPipelineChange{}}
+	diff.diffItems(TektonPipelineChangeSectionEnvPropertiesConst, envPropertyItems(a.EnvProperties), envPropertyItems(b.EnvProperties))
+	diff.diffItems(TektonPipelineChangeSectionInputsConst, inputItems(a.Inputs), inputItems(b.Inputs))
+	diff.diffItems(TektonPipelineChangeSectionTriggersConst, triggerItems(a.Triggers), triggerItems(b.Triggers))
+	diff.diffFields(TektonPipelineChangeSectionWorkerConst, "", workerFields(a.Worker), workerFields(b.Worker))
+	diff.diffFields(TektonPipelineChangeSectionPipelineDefinitionIDConst, "",
+		[]diffField{{"", stringValue(a.PipelineDefinitionID), false}}, []diffField{{"", stringValue(b.PipelineDefinitionID), false}})
+	return diff
+}
+
+// diffItems records the items added, removed and modified between two versions of a section.
+func (diff *TektonPipelineDiff) diffItems(section string, a, b []diffItem) {
+	bByKey := map[string]diffItem{}
+	for _, item := range b {
+		bByKey[item.key] = item
+	}
+	aKeys := map[string]bool{}
+	for _, item := range a {
+		aKeys[item.key] = true
+		other, ok := bByKey[item.key]
+		if !ok {
+			diff.Changes = append(diff.Changes, TektonPipelineChange{
+				Section: section,
+				Kind:    TektonPipelineChangeKindRemovedConst,
+				Key:     item.key,
+				Old:     summarizeFields(item.fields),
+			})
+			continue
+		}
+		diff.diffFields(section, item.key, item.fields, other.fields)
+	}
+	for _, item := range b {
+		if !aKeys[item.key] {
+			diff.Changes = append(diff.Changes, TektonPipelineChange{
+				Section: section,
+				Kind:    TektonPipelineChangeKindAddedConst,
+				Key:     item.key,
+				New:     summarizeFields(item.fields),
+			})
+		}
+	}
+}
+
+// diffFields records the fields modified between two versions of an item. Both versions list the same fields.
+func (diff *TektonPipelineDiff) diffFields(section string, key string, a, b []diffField) {
+	for i := range a {
+		if a[i].value != b[i].value {
+			diff.Changes = append(diff.Changes, TektonPipelineChange{
+				Section: section,
+				Kind:    TektonPipelineChangeKindModifiedConst,
+				Key:     key,
+				Field:   a[i].name,
+				Old:     a[i].display(),
+				New:     b[i].display(),
+			})
+		}
+	}
+}
+
+// summarizeFields renders the fields of an item that are set as space separated field=value pairs.
+func summarizeFields(fields []diffField) string {
+	pairs := []string{}
+	for _, field := range fields {
+		if field.value != "" && field.value != "false" {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", field.name, strconv.Quote(field.display())))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
+// uniqueKeys makes duplicate keys unique by appending the number of the occurrence, starting at 2.
+func uniqueKeys(items []diffItem) []diffItem {
+	seen := map[string]int{}
+	for i := range items {
+		seen[items[i].key]++
+		if n := seen[items[i].key]; n > 1 {
+			items[i].key = fmt.Sprintf("%s#%d", items[i].key, n)
+		}
+	}
+	return items
+}
+
+func envPropertyItems(envProperties []EnvProperty) []diffItem {
+	items := []diffItem{}
+	for _, envProperty := range envProperties {
+		// Changes to secure values are still reported, without revealing them
+		secure := strings.EqualFold(stringValue(envProperty.Type), EnvPropertyTypeSecureConst)
+		items = append(items, diffItem{
+			key: stringValue(envProperty.Name),
+			fields: []diffField{
+				{"value", stringValue(envProperty.Value), secure},
+				{"type", stringValue(envProperty.Type), false},
+			},
+		})
+	}
+	return uniqueKeys(items)
+}
+
+func inputItems(inputs []TektonPipelineInput) []diffItem {
+	items := []diffItem{}
+	for _, input := range inputs {
+		scmSource := input.ScmSource
+		if scmSource == nil {
+			scmSource = &TektonPipelineInputScmSource{}
+		}
+		key := stringValue(input.ServiceInstanceID)
+		if key == "" {
+			key = stringValue(scmSource.URL)
+		}
+		if path := stringValue(scmSource.Path); path != "" {
+			key += ":" + path
+		}
+		items = append(items, diffItem{
+			key: key,
+			fields: []diffField{
+				{"type", stringValue(input.Type), false},
+				{"serviceInstanceId", stringValue(input.ServiceInstanceID), false},
+				{"shardDefinitionId", stringValue(input.ShardDefinitionID), false},
+				{"scmSource.url", stringValue(scmSource.URL), false},
+				{"scmSource.type", stringValue(scmSource.Type), false},
+				{"scmSource.path", stringValue(scmSource.Path), false},
+				{"scmSource.branch", stringValue(scmSource.Branch), false},
+				{"scmSource.blindConnection", boolString(scmSource.BlindConnection), false},
+			},
+		})
+	}
+	return uniqueKeys(items)
+}
+
+func triggerItems(triggers []TektonPipelineTrigger) []diffItem {
+	items := []diffItem{}
+	for _, trigger := range triggers {
+		scmSource := trigger.ScmSource
+		if scmSource == nil {
+			scmSource = &TektonPipelineTriggerScmSource{}
+		}
+		events := trigger.Events
+		if events == nil {
+			events = &TektonPipelineTriggerEvents{}
+		}
+		items = append(items, diffItem{
+			key: triggerName(&trigger),
+			fields: []diffField{
+				{"type", stringValue(trigger.Type), false},
+				{"eventListener", stringValue(trigger.EventListener), false},
+				{"disabled", boolString(trigger.Disabled), false},
+				{"serviceInstanceId", stringValue(trigger.ServiceInstanceID), false},
+				{"scmSource.url", stringValue(scmSource.URL), false},
+				{"scmSource.type", stringValue(scmSource.Type), false},
+				{"scmSource.branch", stringValue(scmSource.Branch), false},
+				{"scmSource.pattern", stringValue(scmSource.Pattern), false},
+				{"events.push", boolString(events.Push), false},
+				{"events.pull_request", boolString(events.PullRequest), false},
+				{"events.pull_request_closed", boolString(events.PullRequestClosed), false},
+			},
+		})
+	}
+	return uniqueKeys(items)
+}
+
+func workerFields(worker *TektonPipelineWorker) []diffField {
+	if worker == nil {
+		worker = &TektonPipelineWorker{}
+	}
+	return []diffField{
+		{"workerId", stringValue(worker.WorkerID), false},
+		{"workerName", stringValue(worker.WorkerName), false},
+		{"workerType", stringValue(worker.WorkerType), false},
+	}
+}
+
+// boolString renders an optional boolean, treating nil as false.
+func boolString(value *bool) string {
+	return strconv.FormatBool(value != nil && *value)
+}
+
+// IsEmpty returns true if the pipelines have the same configuration
+func (diff *TektonPipelineDiff) IsEmpty() bool {
+	return len(diff.Changes) == 0
+}
+
+// Path returns the location of the change, e.g. triggers[manual].scmSource.branch
+func (change *TektonPipelineChange) Path() string {
+	path := change.Section
+	if change.Key != "" {
+		path += fmt.Sprintf("[%s]", change.Key)
+	}
+	if change.Field != "" {
+		path += "." + change.Field
+	}
+	return path
+}
+
+// String returns the change as a single line prefixed by +, - or ~ for added, removed and modified items
+func (change *TektonPipelineChange) String() string {
+	switch change.Kind {
+	case TektonPipelineChangeKindAddedConst:
+		return fmt.Sprintf("+ %s: %s", change.Path(), change.New)
+	case TektonPipelineChangeKindRemovedConst:
+		return fmt.Sprintf("- %s: %s", change.Path(), change.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", change.Path(), strconv.Quote(change.Old), strconv.Quote(change.New))
+	}
+}
+
+// Print writes the changes to w, one per line
+func (diff *TektonPipelineDiff) Print(w io.Writer) error {
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+	for i := range diff.Changes {
+		if _, err := fmt.Fprintln(w, diff.Changes[i].String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintJSON writes the changes to w as indented JSON
+func (diff *TektonPipelineDiff) PrintJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diff)
+}
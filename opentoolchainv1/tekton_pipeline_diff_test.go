@@ -0,0 +1,161 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`DiffTektonPipelines`, func() {
+	var before, after *opentoolchainv1.TektonPipeline
+
+	BeforeEach(func() {
+		unmarshal := func(body string) *opentoolchainv1.TektonPipeline {
+			var raw map[string]json.RawMessage
+			Expect(json.Unmarshal([]byte(body), &raw)).To(Succeed())
+			var pipeline *opentoolchainv1.TektonPipeline
+			Expect(opentoolchainv1.UnmarshalTektonPipeline(raw, &pipeline)).To(Succeed())
+			return pipeline
+		}
+		before = unmarshal(`{
+			"id": "pl1", "name": "pipeline", "toolchainId": "tc1",
+			"created": "2021-07-01T10:00:00.000Z", "updated_at": "2021-07-01T10:00:00.000Z", "runs_url": "https://example.com/pl1/runs",
+			"pipelineDefinitionId": "def1",
+			"worker": {"workerId": "public", "workerName": "IBM Managed workers", "workerType": "public"},
+			"envProperties": [
+				{"name": "version", "value": "1", "type": "TEXT"},
+				{"name": "password", "value": "old-secret", "type": "SECURE"},
+				{"name": "removed", "value": "x", "type": "TEXT"}
+			],
+			"inputs": [
+				{"type": "scm", "serviceInstanceId": "repo1", "shardDefinitionId": "shard1", "scmSource": {"path": ".tekton", "url": "https://github.com/org/repo", "type": "GitHub", "branch": "master"}}
+			],
+			"triggers": [
+				{"id": "t1", "name": "manual", "eventListener": "listener", "type": "manual"},
+				{"id": "t2", "name": "push", "eventListener": "listener", "type": "scm", "serviceInstanceId": "repo1",
+				 "scmSource": {"url": "https://github.com/org/repo", "type": "GitHub", "branch": "master"}, "events": {"push": true}}
+			]
+		}`)
+		after = unmarshal(`{
+			"id": "pl2", "name": "other pipeline", "toolchainId": "tc2",
+			"created": "2021-07-08T10:00:00.000Z", "updated_at": "2021-07-08T10:00:00.000Z", "runs_url": "https://example.com/pl2/runs",
+			"pipelineDefinitionId": "def2",
+			"worker": {"workerId": "private", "workerName": "my worker", "workerType": "private"},
+			"envProperties": [
+				{"name": "version", "value": "2", "type": "TEXT"},
+				{"name": "password", "value": "new-secret", "type": "SECURE"},
+				{"name": "added", "value": "y", "type": "TEXT"}
+			],
+			"inputs": [
+				{"type": "scm", "serviceInstanceId": "repo1", "shardDefinitionId": "shard1", "scmSource": {"path": ".tekton", "url": "https://github.com/org/repo", "type": "GitHub", "branch": "main"}}
+			],
+			"triggers": [
+				{"id": "t3", "name": "manual", "eventListener": "listener", "type": "manual"},
+				{"id": "t4", "name": "push", "eventListener": "listener", "type": "scm", "serviceInstanceId": "repo1", "disabled": true,
+				 "scmSource": {"url": "https://github.com/org/repo", "type": "GitHub", "branch": "main"}, "events": {"push": true}}
+			]
+		}`)
+	})
+
+	It(`Invoke DiffTektonPipelines successfully`, func() {
+		diff := opentoolchainv1.DiffTektonPipelines(before, after)
+		Expect(diff.IsEmpty()).To(BeFalse())
+
+		paths := []string{}
+		for i := range diff.Changes {
+			paths = append(paths, diff.Changes[i].Path())
+		}
+		Expect(paths).To(Equal([]string{
+			"envProperties[version].value",
+			"envProperties[password].value",
+			"envProperties[removed]",
+			"envProperties[added]",
+			"inputs[repo1:.tekton].scmSource.branch",
+			"triggers[push].disabled",
+			"triggers[push].scmSource.branch",
+			"worker.workerId",
+			"worker.workerName",
+			"worker.workerType",
+			"pipelineDefinitionId",
+		}))
+
+		Expect(diff.Changes[0]).To(Equal(opentoolchainv1.TektonPipelineChange{
+			Section: opentoolchainv1.TektonPipelineChangeSectionEnvPropertiesConst,
+			Kind:    opentoolchainv1.TektonPipelineChangeKindModifiedConst,
+			Key:     "version",
+			Field:   "value",
+			Old:     "1",
+			New:     "2",
+		}))
+		Expect(diff.Changes[2].Kind).To(Equal(opentoolchainv1.TektonPipelineChangeKindRemovedConst))
+		Expect(diff.Changes[3].Kind).To(Equal(opentoolchainv1.TektonPipelineChangeKindAddedConst))
+
+		var text bytes.Buffer
+		Expect(diff.Print(&text)).To(Succeed())
+		Expect(text.String()).To(Equal(`~ envProperties[version].value: "1" -> "2"
+~ envProperties[password].value: "[REDACTED]" -> "[REDACTED]"
+- envProperties[removed]: value="x" type="TEXT"
++ envProperties[added]: value="y" type="TEXT"
+~ inputs[repo1:.tekton].scmSource.branch: "master" -> "main"
+~ triggers[push].disabled: "false" -> "true"
+~ triggers[push].scmSource.branch: "master" -> "main"
+~ worker.workerId: "public" -> "private"
+~ worker.workerName: "IBM Managed workers" -> "my worker"
+~ worker.workerType: "public" -> "private"
+~ pipelineDefinitionId: "def1" -> "def2"
+`))
+
+		var data bytes.Buffer
+		Expect(diff.PrintJSON(&data)).To(Succeed())
+		Expect(data.String()).ToNot(ContainSubstring("secret"))
+		var decoded opentoolchainv1.TektonPipelineDiff
+		Expect(json.Unmarshal(data.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(*diff))
+	})
+	It(`Invoke DiffTektonPipelines with identical configurations`, func() {
+		// Server-managed fields and the identity of the pipelines are ignored
+		after.EnvProperties = before.EnvProperties
+		after.Inputs = before.Inputs
+		after.Worker = before.Worker
+		after.PipelineDefinitionID = before.PipelineDefinitionID
+		after.Triggers = append([]opentoolchainv1.TektonPipelineTrigger{}, before.Triggers...)
+		after.Triggers[0].ID = core.StringPtr("t3")
+
+		diff := opentoolchainv1.DiffTektonPipelines(before, after)
+		Expect(diff.IsEmpty()).To(BeTrue())
+
+		var text bytes.Buffer
+		Expect(diff.Print(&text)).To(Succeed())
+		Expect(text.String()).To(Equal("No changes\n"))
+	})
+	It(`Invoke DiffTektonPipelines with a nil pipeline`, func() {
+		diff := opentoolchainv1.DiffTektonPipelines(nil, before)
+		Expect(diff.Changes).To(HaveLen(10))
+		for _, change := range diff.Changes[:6] {
+			Expect(change.Kind).To(Equal(opentoolchainv1.TektonPipelineChangeKindAddedConst))
+		}
+		Expect(diff.Changes[1].New).To(Equal(`value="[REDACTED]" type="SECURE"`))
+		Expect(diff.Changes[5].String()).To(Equal(`+ triggers[push]: type="scm" eventListener="listener" serviceInstanceId="repo1" scmSource.url="https://github.com/org/repo" scmSource.type="GitHub" scmSource.branch="master" events.push="true"`))
+		Expect(opentoolchainv1.DiffTektonPipelines(nil, nil).IsEmpty()).To(BeTrue())
+	})
+})
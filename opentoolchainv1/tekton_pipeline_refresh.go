@@ -0,0 +1,287 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// RefreshTektonPipelineDefinition : Refresh tekton pipeline definition
+// Re-reads the definition of the pipeline from its repositories. Shards without pin are synced to the head of their
+// branch and pinned shards are read at the pinned commit SHA; pins are kept until a refresh without pin for the shard.
+// The SHA of every shard before and after the refresh is returned.
+func (openToolchain *OpenToolchainV1) RefreshTektonPipelineDefinition(refreshTektonPipelineDefinitionOptions *RefreshTektonPipelineDefinitionOptions) (result *TektonPipelineDefinitionRefresh, response *core.DetailedResponse, err error) {
+	return openToolchain.RefreshTektonPipelineDefinitionWithContext(context.Background(), refreshTektonPipelineDefinitionOptions)
+}
+
+// RefreshTektonPipelineDefinitionWithContext is an alternate form of the RefreshTektonPipelineDefinition method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) RefreshTektonPipelineDefinitionWithContext(ctx context.Context, refreshTektonPipelineDefinitionOptions *RefreshTektonPipelineDefinitionOptions) (result *TektonPipelineDefinitionRefresh, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(refreshTektonPipelineDefinitionOptions, "refreshTektonPipelineDefinitionOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(refreshTektonPipelineDefinitionOptions, "refreshTektonPipelineDefinitionOptions")
+	if err != nil {
+		return
+	}
+
+	getTektonPipelineDefinitionOptions := openToolchain.NewGetTektonPipelineDefinitionOptions(*refreshTektonPipelineDefinitionOptions.GUID, *refreshTektonPipelineDefinitionOptions.Region, *refreshTektonPipelineDefinitionOptions.EnvID)
+	getTektonPipelineDefinitionOptions.SetHeaders(refreshTektonPipelineDefinitionOptions.Headers)
+	previous, response, err := openToolchain.GetTektonPipelineDefinitionWithContext(ctx, getTektonPipelineDefinitionOptions)
+	if err != nil {
+		return
+	}
+
+	pins := map[string]string{}
+	if refreshTektonPipelineDefinitionOptions.PinCurrent != nil && *refreshTektonPipelineDefinitionOptions.PinCurrent && previous != nil {
+		pins = previous.Pins()
+	}
+	for shardDefinitionID, sha := range refreshTektonPipelineDefinitionOptions.Pins {
+		pins[shardDefinitionID] = sha
+	}
+
+	shardDefinitionIDs := make([]string, 0, len(pins))
+	for shardDefinitionID := range pins {
+		shardDefinitionIDs = append(shardDefinitionIDs, shardDefinitionID)
+	}
+	sort.Strings(shardDefinitionIDs)
+	shardPins := []ShardPin{}
+	for _, shardDefinitionID := range shardDefinitionIDs {
+		shardPins = append(shardPins, ShardPin{ShardDefinitionID: core.StringPtr(shardDefinitionID), Sha: core.StringPtr(pins[shardDefinitionID])})
+	}
+
+	syncTektonPipelineDefinitionOptions := openToolchain.NewSyncTektonPipelineDefinitionOptions(*refreshTektonPipelineDefinitionOptions.GUID, *refreshTektonPipelineDefinitionOptions.Region, *refreshTektonPipelineDefinitionOptions.EnvID)
+	syncTektonPipelineDefinitionOptions.SetPins(shardPins)
+	syncTektonPipelineDefinitionOptions.SetHeaders(refreshTektonPipelineDefinitionOptions.Headers)
+	definition, response, err := openToolchain.SyncTektonPipelineDefinitionWithContext(ctx, syncTektonPipelineDefinitionOptions)
+	if err != nil {
+		return
+	}
+
+	result = buildTektonPipelineDefinitionRefresh(previous, definition, pins)
+	response.Result = result
+	return
+}
+
+// buildTektonPipelineDefinitionRefresh pairs the shard repositories of a definition before and after a refresh,
+// shards removed by the refresh are listed last.
+func buildTektonPipelineDefinitionRefresh(previous *GetTektonPipelineDefinitionResponse, definition *GetTektonPipelineDefinitionResponse, pins map[string]string) *TektonPipelineDefinitionRefresh {
+	refresh := &TektonPipelineDefinitionRefresh{
+		Definition: definition,
+		Shards:     []ShardRefresh{},
+	}
+
+	oldShas := map[string]string{}
+	if previous != nil {
+		for _, shardRepo := range previous.ShardRepos {
+			oldShas[stringValue(shardRepo.ShardDefinitionID)] = stringValue(shardRepo.Sha)
+		}
+	}
+
+	refreshed := map[string]bool{}
+	if definition != nil {
+		for _, shardRepo := range definition.ShardRepos {
+			shardDefinitionID := stringValue(shardRepo.ShardDefinitionID)
+			_, pinned := pins[shardDefinitionID]
+			refresh.Shards = append(refresh.Shards, ShardRefresh{
+				ShardDefinitionID: shardDefinitionID,
+				RepoURL:           stringValue(shardRepo.RepoURL),
+				Path:              stringValue(shardRepo.Path),
+				OldSha:            oldShas[shardDefinitionID],
+				NewSha:            stringValue(shardRepo.Sha),
+				Pinned:            pinned,
+			})
+			refreshed[shardDefinitionID] = true
+		}
+	}
+	if previous != nil {
+		for _, shardRepo := range previous.ShardRepos {
+			if shardDefinitionID := stringValue(shardRepo.ShardDefinitionID); !refreshed[shardDefinitionID] {
+				refresh.Shards = append(refresh.Shards, ShardRefresh{
+					ShardDefinitionID: shardDefinitionID,
+					RepoURL:           stringValue(shardRepo.RepoURL),
+					Path:              stringValue(shardRepo.Path),
+					OldSha:            stringValue(shardRepo.Sha),
+				})
+			}
+		}
+	}
+	return refresh
+}
+
+// Pins returns the commit SHA of every shard of the definition keyed by shard definition ID, to pin a later refresh
+// to the commits the definition is currently read from, e.g. to reproduce a release
+func (definition *GetTektonPipelineDefinitionResponse) Pins() map[string]string {
+	pins := map[string]string{}
+	for _, shardRepo := range definition.ShardRepos {
+		if shardRepo.ShardDefinitionID != nil && shardRepo.Sha != nil && *shardRepo.Sha != "" {
+			pins[*shardRepo.ShardDefinitionID] = *shardRepo.Sha
+		}
+	}
+	return pins
+}
+
+// RefreshTektonPipelineDefinitionOptions : The RefreshTektonPipelineDefinition options.
+type RefreshTektonPipelineDefinitionOptions struct {
+	// GUID of the pipeline.
+	GUID *string `validate:"required,ne="`
+
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// Commit SHAs to read shards at, keyed by shard definition ID. Shards without pin are synced to their branch head.
+	Pins map[string]string
+
+	// Pin every shard to the commit it is currently read from. Pins take precedence.
+	PinCurrent *bool
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewRefreshTektonPipelineDefinitionOptions : Instantiate RefreshTektonPipelineDefinitionOptions
+func (*OpenToolchainV1) NewRefreshTektonPipelineDefinitionOptions(guid string, region string, envID string) *RefreshTektonPipelineDefinitionOptions {
+	return &RefreshTektonPipelineDefinitionOptions{
+		GUID:   core.StringPtr(guid),
+		Region: core.StringPtr(region),
+		EnvID:  core.StringPtr(envID),
+	}
+}
+
+// SetGUID : Allow user to set GUID
+func (options *RefreshTektonPipelineDefinitionOptions) SetGUID(guid string) *RefreshTektonPipelineDefinitionOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetRegion : Allow user to set Region
+func (options *RefreshTektonPipelineDefinitionOptions) SetRegion(region string) *RefreshTektonPipelineDefinitionOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *RefreshTektonPipelineDefinitionOptions) SetEnvID(envID string) *RefreshTektonPipelineDefinitionOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetPins : Allow user to set Pins
+func (options *RefreshTektonPipelineDefinitionOptions) SetPins(pins map[string]string) *RefreshTektonPipelineDefinitionOptions {
+	options.Pins = pins
+	return options
+}
+
+// SetPin : Allow user to pin a shard to a commit SHA
+func (options *RefreshTektonPipelineDefinitionOptions) SetPin(shardDefinitionID string, sha string) *RefreshTektonPipelineDefinitionOptions {
+	if options.Pins == nil {
+		options.Pins = map[string]string{}
+	}
+	options.Pins[shardDefinitionID] = sha
+	return options
+}
+
+// SetPinCurrent : Allow user to set PinCurrent
+func (options *RefreshTektonPipelineDefinitionOptions) SetPinCurrent(pinCurrent bool) *RefreshTektonPipelineDefinitionOptions {
+	options.PinCurrent = core.BoolPtr(pinCurrent)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *RefreshTektonPipelineDefinitionOptions) SetHeaders(param map[string]string) *RefreshTektonPipelineDefinitionOptions {
+	options.Headers = param
+	return options
+}
+
+// TektonPipelineDefinitionRefresh : Result of RefreshTektonPipelineDefinition
+type TektonPipelineDefinitionRefresh struct {
+	// Definition after the refresh.
+	Definition *GetTektonPipelineDefinitionResponse
+
+	// Shards in the order of the refreshed definition.
+	Shards []ShardRefresh
+}
+
+// Changed returns the shards whose commit SHA changed
+func (refresh *TektonPipelineDefinitionRefresh) Changed() []ShardRefresh {
+	changed := []ShardRefresh{}
+	for _, shard := range refresh.Shards {
+		if shard.Changed() {
+			changed = append(changed, shard)
+		}
+	}
+	return changed
+}
+
+// Print writes one line per shard with its old and new commit SHA
+func (refresh *TektonPipelineDefinitionRefresh) Print(w io.Writer) error {
+	for _, shard := range refresh.Shards {
+		status := "unchanged"
+		if shard.Changed() {
+			status = fmt.Sprintf("%s -> %s", shortSha(shard.OldSha), shortSha(shard.NewSha))
+		}
+		if shard.Pinned {
+			status += " (pinned)"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s: %s\n", shard.RepoURL, shard.Path, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shortSha abbreviates a commit SHA, a missing SHA is shown as "none".
+func shortSha(sha string) string {
+	if sha == "" {
+		return "none"
+	}
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// ShardRefresh : Commit SHAs of a shard of the definition before and after a refresh
+type ShardRefresh struct {
+	ShardDefinitionID string
+
+	RepoURL string
+
+	Path string
+
+	// SHA before the refresh, empty if the shard was not read yet.
+	OldSha string
+
+	// SHA after the refresh, empty if the shard was removed from the definition.
+	NewSha string
+
+	// Whether the shard was pinned by the refresh.
+	Pinned bool
+}
+
+// Changed returns true if the commit SHA of the shard changed
+func (shard *ShardRefresh) Changed() bool {
+	return shard.OldSha != shard.NewSha
+}
@@ -0,0 +1,137 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`RefreshTektonPipelineDefinition`, func() {
+	var testServer *httptest.Server
+	var pins []interface{}
+
+	BeforeEach(func() {
+		pins = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "defs", "pipelineId": "pl1", "shardRepos": [
+					{"shardDefinitionId": "def1", "repoUrl": "https://github.com/org/app", "path": ".tekton", "sha": "1111111111"},
+					{"shardDefinitionId": "def2", "repoUrl": "https://github.com/org/tasks", "path": "tasks", "sha": "2222222222"},
+					{"shardDefinitionId": "def3", "repoUrl": "https://github.com/org/old", "path": "old", "sha": "3333333333"}]}`)
+			case req.Method == "POST" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition/refresh":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				pins = body["pins"].([]interface{})
+				def1Sha := "4444444444"
+				for _, pin := range pins {
+					if pin.(map[string]interface{})["shardDefinitionId"] == "def1" {
+						def1Sha = pin.(map[string]interface{})["sha"].(string)
+					}
+				}
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"id": "defs", "pipelineId": "pl1", "shardRepos": [
+					{"shardDefinitionId": "def1", "repoUrl": "https://github.com/org/app", "path": ".tekton", "sha": "%s"},
+					{"shardDefinitionId": "def2", "repoUrl": "https://github.com/org/tasks", "path": "tasks", "sha": "2222222222"},
+					{"shardDefinitionId": "def4", "repoUrl": "https://github.com/org/new", "path": "new", "sha": "5555555555"}]}`, def1Sha)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke RefreshTektonPipelineDefinition successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		refreshTektonPipelineDefinitionOptionsModel := openToolchainService.NewRefreshTektonPipelineDefinitionOptions("pl1", "us-south", "ibm:yp:us-south")
+		result, response, err := openToolchainService.RefreshTektonPipelineDefinition(refreshTektonPipelineDefinitionOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(pins).To(BeEmpty())
+		Expect(result.Shards).To(HaveLen(4))
+		Expect(result.Shards[0].OldSha).To(Equal("1111111111"))
+		Expect(result.Shards[0].NewSha).To(Equal("4444444444"))
+		Expect(result.Changed()).To(HaveLen(3))
+
+		var report bytes.Buffer
+		Expect(result.Print(&report)).To(Succeed())
+		Expect(report.String()).To(Equal(`https://github.com/org/app .tekton: 1111111 -> 4444444
+https://github.com/org/tasks tasks: unchanged
+https://github.com/org/new new: none -> 5555555
+https://github.com/org/old old: 3333333 -> none
+`))
+	})
+	It(`Invoke RefreshTektonPipelineDefinition with pins successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		refreshTektonPipelineDefinitionOptionsModel := openToolchainService.NewRefreshTektonPipelineDefinitionOptions("pl1", "us-south", "ibm:yp:us-south")
+		refreshTektonPipelineDefinitionOptionsModel.SetPin("def1", "0000000000")
+		result, _, err := openToolchainService.RefreshTektonPipelineDefinition(refreshTektonPipelineDefinitionOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(pins).To(Equal([]interface{}{map[string]interface{}{"shardDefinitionId": "def1", "sha": "0000000000"}}))
+		Expect(result.Shards[0].NewSha).To(Equal("0000000000"))
+		Expect(result.Shards[0].Pinned).To(BeTrue())
+		Expect(result.Shards[1].Pinned).To(BeFalse())
+
+		// Pinning the current commits keeps the definition where it is, explicit pins take precedence
+		refreshTektonPipelineDefinitionOptionsModel.SetPinCurrent(true).SetPins(map[string]string{"def2": "9999999999"})
+		result, _, err = openToolchainService.RefreshTektonPipelineDefinition(refreshTektonPipelineDefinitionOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(pins).To(HaveLen(3))
+		Expect(pins[1]).To(Equal(map[string]interface{}{"shardDefinitionId": "def2", "sha": "9999999999"}))
+		Expect(result.Shards[0].Changed()).To(BeFalse())
+		Expect(result.Definition.Pins()).To(HaveKeyWithValue("def1", "1111111111"))
+	})
+	It(`Invoke RefreshTektonPipelineDefinition with error: Operation validation error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, response, err := openToolchainService.RefreshTektonPipelineDefinition(openToolchainService.NewRefreshTektonPipelineDefinitionOptions("pl1", "", "ibm:yp:us-south"))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
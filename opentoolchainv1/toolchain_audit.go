@@ -0,0 +1,277 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Constants associated with the AuditFinding.Kind property.
+const (
+	// A pipeline input or trigger references a service instance that is not part of the toolchain.
+	AuditFindingKindDanglingReferenceConst = "dangling_reference"
+
+	// A repository tool integration is not referenced by any pipeline. Tools without repository, such as Slack or Key
+	// Protect, are not meant to be referenced by pipelines and are not reported.
+	AuditFindingKindUnusedToolConst = "unused_tool"
+
+	// A disabled trigger points at a repository that is no longer integrated with the toolchain.
+	AuditFindingKindDisabledTriggerConst = "disabled_trigger_removed_repo"
+)
+
+// ToolchainLocator : Identifies a toolchain to operate on
+type ToolchainLocator struct {
+	// Toolchain region.
+	Region string `json:"region"`
+
+	// GUID of the toolchain.
+	GUID string `json:"guid"`
+
+	// Environment ID.
+	EnvID string `json:"env_id"`
+}
+
+// AuditFinding : A problem found while auditing a toolchain
+type AuditFinding struct {
+	// One of the AuditFindingKind constants.
+	Kind string `json:"kind"`
+
+	ToolchainID string `json:"toolchain_id"`
+
+	PipelineID string `json:"pipeline_id,omitempty"`
+
+	// Where the reference was found, one of the ToolReferenceSource constants.
+	Source string `json:"source,omitempty"`
+
+	// ID of the trigger holding the reference.
+	TriggerID string `json:"trigger_id,omitempty"`
+
+	ServiceInstanceID string `json:"service_instance_id,omitempty"`
+
+	ServiceID string `json:"service_id,omitempty"`
+
+	RepoURL string `json:"repo_url,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// AuditError : A toolchain that could not be audited
+type AuditError struct {
+	Toolchain ToolchainLocator `json:"toolchain"`
+
+	Message string `json:"message"`
+}
+
+// AuditReport : The result of auditing a set of toolchains
+type AuditReport struct {
+	// Number of toolchains audited successfully.
+	Scanned int `json:"scanned"`
+
+	Findings []AuditFinding `json:"findings"`
+
+	Errors []AuditError `json:"errors"`
+}
+
+// Count returns the number of findings of the specified kind
+func (report *AuditReport) Count(kind string) int {
+	count := 0
+	for _, finding := range report.Findings {
+		if finding.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteJSON writes the report to w as indented JSON
+func (report *AuditReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// AuditToolchainGraph : Report dangling references, unused repository integrations and disabled triggers pointing at
+// removed repositories in a toolchain graph
+func AuditToolchainGraph(graph *ToolchainGraph) []AuditFinding {
+	findings := []AuditFinding{}
+	if graph == nil || graph.Toolchain == nil {
+		return findings
+	}
+
+	toolchainID := ""
+	if graph.Toolchain.ToolchainGUID != nil {
+		toolchainID = *graph.Toolchain.ToolchainGUID
+	}
+
+	repoURLs := make(map[string]bool)
+	for _, node := range graph.Tools {
+		if repoURL, ok := node.Service.Parameters["repo_url"].(string); ok && repoURL != "" {
+			repoURLs[NormalizeRepoURL(repoURL)] = true
+		}
+	}
+
+	for _, pipeline := range graph.Pipelines {
+		pipelineID := ""
+		if pipeline.ID != nil {
+			pipelineID = *pipeline.ID
+		}
+		for _, input := range pipeline.Inputs {
+			if input.ServiceInstanceID == nil || graph.Tool(*input.ServiceInstanceID) != nil {
+				continue
+			}
+			finding := AuditFinding{
+				Kind:              AuditFindingKindDanglingReferenceConst,
+				ToolchainID:       toolchainID,
+				PipelineID:        pipelineID,
+				Source:            ToolReferenceSourceInputConst,
+				ServiceInstanceID: *input.ServiceInstanceID,
+				Message:           fmt.Sprintf("input references missing service instance %s", *input.ServiceInstanceID),
+			}
+			if input.ScmSource != nil && input.ScmSource.URL != nil {
+				finding.RepoURL = *input.ScmSource.URL
+			}
+			findings = append(findings, finding)
+		}
+		for _, trigger := range pipeline.Triggers {
+			finding := AuditFinding{
+				ToolchainID: toolchainID,
+				PipelineID:  pipelineID,
+				Source:      ToolReferenceSourceTriggerConst,
+			}
+			if trigger.ID != nil {
+				finding.TriggerID = *trigger.ID
+			}
+			if trigger.ScmSource != nil && trigger.ScmSource.URL != nil {
+				finding.RepoURL = *trigger.ScmSource.URL
+			}
+			disabled := trigger.Disabled != nil && *trigger.Disabled
+
+			switch {
+			case trigger.ServiceInstanceID != nil && graph.Tool(*trigger.ServiceInstanceID) == nil:
+				finding.ServiceInstanceID = *trigger.ServiceInstanceID
+				if disabled {
+					finding.Kind = AuditFindingKindDisabledTriggerConst
+					finding.Message = fmt.Sprintf("disabled trigger references missing service instance %s", *trigger.ServiceInstanceID)
+				} else {
+					finding.Kind = AuditFindingKindDanglingReferenceConst
+					finding.Message = fmt.Sprintf("trigger references missing service instance %s", *trigger.ServiceInstanceID)
+				}
+			case disabled && finding.RepoURL != "" && !repoURLs[NormalizeRepoURL(finding.RepoURL)]:
+				finding.Kind = AuditFindingKindDisabledTriggerConst
+				finding.Message = fmt.Sprintf("disabled trigger points at repository %s which is not integrated with the toolchain", finding.RepoURL)
+			default:
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	for _, node := range graph.Orphaned() {
+		repoURL, ok := node.Service.Parameters["repo_url"].(string)
+		if !ok || repoURL == "" {
+			continue
+		}
+		finding := AuditFinding{
+			Kind:              AuditFindingKindUnusedToolConst,
+			ToolchainID:       toolchainID,
+			ServiceInstanceID: node.InstanceID(),
+			RepoURL:           repoURL,
+			Message:           "repository integration is not referenced by any pipeline",
+		}
+		if node.Service.ServiceID != nil {
+			finding.ServiceID = *node.Service.ServiceID
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// AuditToolchains : Audit a set of toolchains for orphaned integrations
+// Each toolchain is fetched with GetToolchainGraph and checked with AuditToolchainGraph. Toolchains that cannot be
+// fetched are recorded in the report errors and do not stop the audit.
+func (openToolchain *OpenToolchainV1) AuditToolchains(auditToolchainsOptions *AuditToolchainsOptions) (result *AuditReport, err error) {
+	return openToolchain.AuditToolchainsWithContext(context.Background(), auditToolchainsOptions)
+}
+
+// AuditToolchainsWithContext is an alternate form of the AuditToolchains method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) AuditToolchainsWithContext(ctx context.Context, auditToolchainsOptions *AuditToolchainsOptions) (result *AuditReport, err error) {
+	err = core.ValidateNotNil(auditToolchainsOptions, "auditToolchainsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(auditToolchainsOptions, "auditToolchainsOptions")
+	if err != nil {
+		return
+	}
+
+	result = &AuditReport{
+		Findings: []AuditFinding{},
+		Errors:   []AuditError{},
+	}
+	for _, locator := range auditToolchainsOptions.Toolchains {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		getToolchainGraphOptions := openToolchain.NewGetToolchainGraphOptions(locator.Region, locator.GUID, locator.EnvID)
+		getToolchainGraphOptions.SetHeaders(auditToolchainsOptions.Headers)
+		graph, graphErr := openToolchain.GetToolchainGraphWithContext(ctx, getToolchainGraphOptions)
+		if graphErr != nil {
+			result.Errors = append(result.Errors, AuditError{Toolchain: locator, Message: graphErr.Error()})
+			continue
+		}
+
+		result.Scanned++
+		result.Findings = append(result.Findings, AuditToolchainGraph(graph)...)
+	}
+
+	return
+}
+
+// AuditToolchainsOptions : The AuditToolchains options.
+type AuditToolchainsOptions struct {
+	// Toolchains to audit.
+	Toolchains []ToolchainLocator `validate:"required,min=1"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewAuditToolchainsOptions : Instantiate AuditToolchainsOptions
+func (*OpenToolchainV1) NewAuditToolchainsOptions(toolchains []ToolchainLocator) *AuditToolchainsOptions {
+	return &AuditToolchainsOptions{
+		Toolchains: toolchains,
+	}
+}
+
+// SetToolchains : Allow user to set Toolchains
+func (options *AuditToolchainsOptions) SetToolchains(toolchains []ToolchainLocator) *AuditToolchainsOptions {
+	options.Toolchains = toolchains
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *AuditToolchainsOptions) SetHeaders(param map[string]string) *AuditToolchainsOptions {
+	options.Headers = param
+	return options
+}
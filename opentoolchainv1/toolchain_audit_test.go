@@ -0,0 +1,160 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ToolchainAudit`, func() {
+	var testServer *httptest.Server
+
+	Describe(`AuditToolchainGraph(graph *ToolchainGraph)`, func() {
+		It(`Report dangling references, unused tools and disabled triggers`, func() {
+			toolchain := &opentoolchainv1.Toolchain{
+				ToolchainGUID: core.StringPtr("tc1"),
+				Services: []opentoolchainv1.Service{
+					{ServiceID: core.StringPtr("pipeline"), InstanceID: core.StringPtr("pl1")},
+					{ServiceID: core.StringPtr("githubconsolidated"), InstanceID: core.StringPtr("repo1"), Parameters: map[string]interface{}{"repo_url": "https://github.com/org/app"}},
+					{ServiceID: core.StringPtr("githubconsolidated"), InstanceID: core.StringPtr("repo2"), Parameters: map[string]interface{}{"repo_url": "https://github.com/org/unused"}},
+					{ServiceID: core.StringPtr("slack"), InstanceID: core.StringPtr("slack1")},
+				},
+			}
+			pipeline := opentoolchainv1.TektonPipeline{
+				ID: core.StringPtr("pl1"),
+				Inputs: []opentoolchainv1.TektonPipelineInput{
+					{ServiceInstanceID: core.StringPtr("repo1")},
+					{ServiceInstanceID: core.StringPtr("gone1"), ScmSource: &opentoolchainv1.TektonPipelineInputScmSource{URL: core.StringPtr("https://github.com/org/gone")}},
+				},
+				Triggers: []opentoolchainv1.TektonPipelineTrigger{
+					{ID: core.StringPtr("t1"), ServiceInstanceID: core.StringPtr("repo1")},
+					{ID: core.StringPtr("t2"), ServiceInstanceID: core.StringPtr("gone2")},
+					{ID: core.StringPtr("t3"), ServiceInstanceID: core.StringPtr("gone3"), Disabled: core.BoolPtr(true)},
+					{ID: core.StringPtr("t4"), Disabled: core.BoolPtr(true), ScmSource: &opentoolchainv1.TektonPipelineTriggerScmSource{URL: core.StringPtr("https://github.com/org/old.git")}},
+					{ID: core.StringPtr("t5"), Disabled: core.BoolPtr(true), ScmSource: &opentoolchainv1.TektonPipelineTriggerScmSource{URL: core.StringPtr("https://github.com/org/app.git")}},
+				},
+			}
+			graph := opentoolchainv1.BuildToolchainGraph(toolchain, []opentoolchainv1.TektonPipeline{pipeline}, nil)
+
+			findings := opentoolchainv1.AuditToolchainGraph(graph)
+			Expect(findings).To(HaveLen(5))
+			Expect(findings[0].Kind).To(Equal(opentoolchainv1.AuditFindingKindDanglingReferenceConst))
+			Expect(findings[0].Source).To(Equal(opentoolchainv1.ToolReferenceSourceInputConst))
+			Expect(findings[0].ServiceInstanceID).To(Equal("gone1"))
+			Expect(findings[0].RepoURL).To(Equal("https://github.com/org/gone"))
+			Expect(findings[1].Kind).To(Equal(opentoolchainv1.AuditFindingKindDanglingReferenceConst))
+			Expect(findings[1].TriggerID).To(Equal("t2"))
+			Expect(findings[2].Kind).To(Equal(opentoolchainv1.AuditFindingKindDisabledTriggerConst))
+			Expect(findings[2].TriggerID).To(Equal("t3"))
+			Expect(findings[3].Kind).To(Equal(opentoolchainv1.AuditFindingKindDisabledTriggerConst))
+			Expect(findings[3].TriggerID).To(Equal("t4"))
+			Expect(findings[4].Kind).To(Equal(opentoolchainv1.AuditFindingKindUnusedToolConst))
+			Expect(findings[4].ServiceInstanceID).To(Equal("repo2"))
+			Expect(findings[4].ServiceID).To(Equal("githubconsolidated"))
+			Expect(findings[4].RepoURL).To(Equal("https://github.com/org/unused"))
+		})
+		It(`Handle a nil graph`, func() {
+			Expect(opentoolchainv1.AuditToolchainGraph(nil)).To(BeEmpty())
+		})
+	})
+
+	Describe(`AuditToolchains(auditToolchainsOptions *AuditToolchainsOptions)`, func() {
+		BeforeEach(func() {
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				path := req.URL.Path
+				res.Header().Set("Content-type", "application/json")
+				switch {
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+					res.WriteHeader(200)
+					fmt.Fprint(res, graphToolchainResponse)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition",
+					path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl2/definition":
+					Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"pipelineId": "pl", "id": "d"}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl1", "name": "pl1", "toolchainId": "tc1", "envProperties": [], "triggers": [{"id": "t1", "eventListener": "l", "type": "scm", "serviceInstanceId": "removed", "disabled": true}]}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl2":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl2", "name": "pl2", "toolchainId": "tc1", "envProperties": [], "inputs": [{"serviceInstanceId": "repo1"}, {"serviceInstanceId": "repo2"}]}`)
+				default:
+					res.WriteHeader(404)
+					fmt.Fprint(res, `{"message": "not found"}`)
+				}
+			}))
+		})
+		It(`Invoke AuditToolchains successfully`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			auditToolchainsOptionsModel := openToolchainService.NewAuditToolchainsOptions([]opentoolchainv1.ToolchainLocator{
+				{Region: "us-south", GUID: "tc1", EnvID: "ibm:yp:us-south"},
+				{Region: "us-south", GUID: "tc2", EnvID: "ibm:yp:us-south"},
+			})
+			report, err := openToolchainService.AuditToolchains(auditToolchainsOptionsModel)
+			Expect(err).To(BeNil())
+			// tc1 has two Tekton pipelines, their definitions are read from the region of the toolchain
+			Expect(report.Scanned).To(Equal(1))
+			Expect(report.Errors).To(HaveLen(1))
+			Expect(report.Errors[0].Toolchain.GUID).To(Equal("tc2"))
+			Expect(report.Count(opentoolchainv1.AuditFindingKindDisabledTriggerConst)).To(Equal(1))
+			// slack1 is not referenced either, but it is not a repository integration
+			Expect(report.Count(opentoolchainv1.AuditFindingKindUnusedToolConst)).To(Equal(0))
+			Expect(report.Count(opentoolchainv1.AuditFindingKindDanglingReferenceConst)).To(Equal(0))
+
+			var buf bytes.Buffer
+			Expect(report.WriteJSON(&buf)).To(Succeed())
+			var decoded map[string]interface{}
+			Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded["scanned"]).To(Equal(float64(1)))
+			Expect(decoded["findings"]).To(HaveLen(1))
+		})
+		It(`Invoke AuditToolchains with error: Operation validation error`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			report, err := openToolchainService.AuditToolchains(nil)
+			Expect(err).ToNot(BeNil())
+			Expect(report).To(BeNil())
+
+			report, err = openToolchainService.AuditToolchains(openToolchainService.NewAuditToolchainsOptions(nil))
+			Expect(err).ToNot(BeNil())
+			Expect(report).To(BeNil())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
@@ -0,0 +1,209 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// defaultToolchainGUIDsPerRequest is the default number of comma-separated GUIDs fetched by one GetToolchain request.
+// The API documents no limit, the default keeps the request URL short.
+const defaultToolchainGUIDsPerRequest = 20
+
+// GetToolchains : Fetch several toolchains
+// The GUIDs are deduplicated and fetched with GetToolchain in chunks of at most ChunkSize comma-separated GUIDs. Since
+// the API may answer a whole chunk with a 404, the GUIDs of such a chunk are then fetched one by one. The toolchains
+// found are keyed by GUID and the GUIDs the API returned nothing for are reported in NotFound.
+func (openToolchain *OpenToolchainV1) GetToolchains(getToolchainsOptions *GetToolchainsOptions) (result *ToolchainBatch, response *core.DetailedResponse, err error) {
+	return openToolchain.GetToolchainsWithContext(context.Background(), getToolchainsOptions)
+}
+
+// GetToolchainsWithContext is an alternate form of the GetToolchains method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) GetToolchainsWithContext(ctx context.Context, getToolchainsOptions *GetToolchainsOptions) (result *ToolchainBatch, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(getToolchainsOptions, "getToolchainsOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(getToolchainsOptions, "getToolchainsOptions")
+	if err != nil {
+		return
+	}
+
+	chunkSize := defaultToolchainGUIDsPerRequest
+	if getToolchainsOptions.ChunkSize != nil && *getToolchainsOptions.ChunkSize > 0 {
+		chunkSize = int(*getToolchainsOptions.ChunkSize)
+	}
+
+	guids := []string{}
+	seen := map[string]bool{}
+	for _, guid := range getToolchainsOptions.GUIDs {
+		guid = strings.TrimSpace(guid)
+		if guid != "" && !seen[guid] {
+			seen[guid] = true
+			guids = append(guids, guid)
+		}
+	}
+
+	batch := &ToolchainBatch{Toolchains: map[string]*Toolchain{}}
+	for start := 0; start < len(guids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(guids) {
+			end = len(guids)
+		}
+		chunk := guids[start:end]
+
+		var notFound bool
+		notFound, response, err = openToolchain.getToolchainChunk(ctx, getToolchainsOptions, chunk, batch)
+		if err != nil {
+			return
+		}
+		if notFound && len(chunk) > 1 {
+			for _, guid := range chunk {
+				_, response, err = openToolchain.getToolchainChunk(ctx, getToolchainsOptions, []string{guid}, batch)
+				if err != nil {
+					return
+				}
+			}
+		}
+		for _, guid := range chunk {
+			if batch.Toolchains[guid] == nil {
+				batch.NotFound = append(batch.NotFound, guid)
+			}
+		}
+	}
+
+	result = batch
+	return
+}
+
+// getToolchainChunk fetches the toolchains of a chunk of GUIDs into the batch and returns true if the API answered
+// with a 404.
+func (openToolchain *OpenToolchainV1) getToolchainChunk(ctx context.Context, getToolchainsOptions *GetToolchainsOptions, chunk []string, batch *ToolchainBatch) (notFound bool, response *core.DetailedResponse, err error) {
+	getToolchainOptions := openToolchain.NewGetToolchainOptions(*getToolchainsOptions.Region, strings.Join(chunk, ","))
+	if getToolchainsOptions.Include != nil {
+		getToolchainOptions.SetInclude(*getToolchainsOptions.Include)
+	}
+	getToolchainOptions.SetHeaders(getToolchainsOptions.Headers)
+	toolchainResponse, response, err := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+	if err != nil {
+		if response == nil || response.StatusCode != 404 {
+			return
+		}
+		return true, response, nil
+	}
+	if toolchainResponse != nil {
+		requested := map[string]bool{}
+		for _, guid := range chunk {
+			requested[guid] = true
+		}
+		for i := range toolchainResponse.Items {
+			toolchain := &toolchainResponse.Items[i]
+			if guid := stringValue(toolchain.ToolchainGUID); requested[guid] {
+				batch.Toolchains[guid] = toolchain
+			}
+		}
+	}
+	return
+}
+
+// GetToolchainsOptions : The GetToolchains options.
+type GetToolchainsOptions struct {
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// GUIDs of the toolchains.
+	GUIDs []string `validate:"required,min=1"`
+
+	// Instructs the API to return the specified content according to the comma-separated list of sections.
+	Include *string
+
+	// Maximum number of GUIDs fetched by one GetToolchain request. Defaults to 20.
+	ChunkSize *int64
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewGetToolchainsOptions : Instantiate GetToolchainsOptions
+func (*OpenToolchainV1) NewGetToolchainsOptions(region string, guids []string) *GetToolchainsOptions {
+	return &GetToolchainsOptions{
+		Region: core.StringPtr(region),
+		GUIDs:  guids,
+	}
+}
+
+// SetRegion : Allow user to set Region
+func (options *GetToolchainsOptions) SetRegion(region string) *GetToolchainsOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetGUIDs : Allow user to set GUIDs
+func (options *GetToolchainsOptions) SetGUIDs(guids []string) *GetToolchainsOptions {
+	options.GUIDs = guids
+	return options
+}
+
+// SetInclude : Allow user to set Include
+func (options *GetToolchainsOptions) SetInclude(include string) *GetToolchainsOptions {
+	options.Include = core.StringPtr(include)
+	return options
+}
+
+// SetIncludes : Allow user to set Include from a set of sections
+func (options *GetToolchainsOptions) SetIncludes(includes *ToolchainIncludes) *GetToolchainsOptions {
+	return options.SetInclude(includes.String())
+}
+
+// SetChunkSize : Allow user to set ChunkSize
+func (options *GetToolchainsOptions) SetChunkSize(chunkSize int64) *GetToolchainsOptions {
+	options.ChunkSize = core.Int64Ptr(chunkSize)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *GetToolchainsOptions) SetHeaders(param map[string]string) *GetToolchainsOptions {
+	options.Headers = param
+	return options
+}
+
+// ToolchainBatch : Toolchains fetched by GetToolchains
+type ToolchainBatch struct {
+	// Toolchains found, keyed by GUID.
+	Toolchains map[string]*Toolchain
+
+	// GUIDs of the toolchains not found, in the order they were requested.
+	NotFound []string
+}
+
+// Get returns the toolchain with the GUID, or nil if it was not found
+func (batch *ToolchainBatch) Get(guid string) *Toolchain {
+	return batch.Toolchains[guid]
+}
+
+// IsNotFound returns true if the toolchain with the GUID was requested but not found
+func (batch *ToolchainBatch) IsNotFound(guid string) bool {
+	for _, notFound := range batch.NotFound {
+		if notFound == guid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`GetToolchains`, func() {
+	var testServer *httptest.Server
+	var requestedGUIDs [][]string
+
+	BeforeEach(func() {
+		requestedGUIDs = nil
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("GET"))
+			Expect(req.URL.Query().Get("include")).To(Equal("fields"))
+			guids := strings.Split(req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:], ",")
+			requestedGUIDs = append(requestedGUIDs, guids)
+
+			res.Header().Set("Content-type", "application/json")
+			if guids[0] == "error" {
+				res.WriteHeader(500)
+				fmt.Fprint(res, `{"message": "internal error"}`)
+				return
+			}
+			items := []string{}
+			for _, guid := range guids {
+				if !strings.HasPrefix(guid, "missing") && !strings.HasPrefix(guid, "deleted") {
+					items = append(items, fmt.Sprintf(`{"toolchain_guid": "%s", "name": "toolchain %s"}`, guid, guid))
+				}
+			}
+			if len(items) == 0 || (len(guids) > 1 && strings.Contains(strings.Join(guids, ","), "deleted")) {
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+				return
+			}
+			res.WriteHeader(200)
+			fmt.Fprintf(res, `{"total_results": %d, "items": [%s]}`, len(items), strings.Join(items, ","))
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke GetToolchains successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		getToolchainsOptionsModel := openToolchainService.NewGetToolchainsOptions("us-south", []string{"tc1", "missing1", "tc2", "tc1", "missing2", "missing3", "tc3"})
+		getToolchainsOptionsModel.SetIncludes(opentoolchainv1.NewToolchainIncludes().WithFields())
+		getToolchainsOptionsModel.SetChunkSize(2)
+		result, response, err := openToolchainService.GetToolchains(getToolchainsOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(200))
+		Expect(requestedGUIDs).To(Equal([][]string{{"tc1", "missing1"}, {"tc2", "missing2"}, {"missing3", "tc3"}}))
+		Expect(result.Toolchains).To(HaveLen(3))
+		Expect(*result.Get("tc2").Name).To(Equal("toolchain tc2"))
+		Expect(result.Get("missing1")).To(BeNil())
+		Expect(result.NotFound).To(Equal([]string{"missing1", "missing2", "missing3"}))
+		Expect(result.IsNotFound("missing2")).To(BeTrue())
+		Expect(result.IsNotFound("tc3")).To(BeFalse())
+
+		// Chunks default to 20 GUIDs
+		guids := []string{}
+		for i := 0; i < 25; i++ {
+			guids = append(guids, fmt.Sprintf("tc%d", i))
+		}
+		requestedGUIDs = nil
+		getToolchainsOptionsModel.SetGUIDs(guids).ChunkSize = nil
+		result, _, err = openToolchainService.GetToolchains(getToolchainsOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(requestedGUIDs).To(HaveLen(2))
+		Expect(requestedGUIDs[0]).To(HaveLen(20))
+		Expect(result.Toolchains).To(HaveLen(25))
+		Expect(result.NotFound).To(BeEmpty())
+
+		// The chunk size is not capped
+		requestedGUIDs = nil
+		getToolchainsOptionsModel.SetChunkSize(100)
+		result, _, err = openToolchainService.GetToolchains(getToolchainsOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(requestedGUIDs).To(HaveLen(1))
+		Expect(result.Toolchains).To(HaveLen(25))
+
+		// The GUIDs of a chunk answered with a 404 are fetched one by one
+		requestedGUIDs = nil
+		getToolchainsOptionsModel.SetGUIDs([]string{"tc1", "deleted1", "tc2"}).SetChunkSize(3)
+		result, _, err = openToolchainService.GetToolchains(getToolchainsOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(requestedGUIDs).To(Equal([][]string{{"tc1", "deleted1", "tc2"}, {"tc1"}, {"deleted1"}, {"tc2"}}))
+		Expect(result.Toolchains).To(HaveLen(2))
+		Expect(result.Get("tc2")).ToNot(BeNil())
+		Expect(result.NotFound).To(Equal([]string{"deleted1"}))
+	})
+	It(`Invoke GetToolchains with error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		getToolchainsOptionsModel := openToolchainService.NewGetToolchainsOptions("us-south", []string{"error"})
+		getToolchainsOptionsModel.SetInclude("fields")
+		result, response, err := openToolchainService.GetToolchains(getToolchainsOptionsModel)
+		Expect(err).ToNot(BeNil())
+		Expect(response.StatusCode).To(Equal(500))
+		Expect(result).To(BeNil())
+
+		result, response, err = openToolchainService.GetToolchains(openToolchainService.NewGetToolchainsOptions("us-south", nil))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(result).To(BeNil())
+		Expect(requestedGUIDs).To(HaveLen(1))
+	})
+})
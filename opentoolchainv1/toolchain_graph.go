@@ -0,0 +1,614 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// pipelineServiceID is the service_id of pipeline tool integrations.
+const pipelineServiceID = "pipeline"
+
+// tektonPipelineType is the value of the "type" parameter of Tekton pipeline tool integrations.
+const tektonPipelineType = "tekton"
+
+// Constants associated with the ToolReference.Source property.
+const (
+	ToolReferenceSourceInputConst     = "input"
+	ToolReferenceSourceTriggerConst   = "trigger"
+	ToolReferenceSourceShardRepoConst = "shard_repo"
+)
+
+// Constants associated with the DeletionStep.Kind property.
+const (
+	DeletionStepKindPipelineConst  = "pipeline"
+	DeletionStepKindToolConst      = "tool"
+	DeletionStepKindToolchainConst = "toolchain"
+)
+
+// ToolchainGraph : Dependency graph between the tool integrations of a toolchain and its Tekton pipelines
+type ToolchainGraph struct {
+	Toolchain *Toolchain
+
+	// Tool integrations in the order they are listed in Toolchain.Services.
+	Tools []ToolNode
+
+	// Pipeline configuration and definitions the graph was built from.
+	Pipelines []TektonPipeline
+
+	Definitions []GetTektonPipelineDefinitionResponse
+}
+
+// ToolNode : A tool integration and the pipeline configuration that references it
+type ToolNode struct {
+	Service *Service
+
+	References []ToolReference
+}
+
+// ToolReference : A reference from a pipeline to a tool integration
+type ToolReference struct {
+	// ID of the referencing pipeline.
+	PipelineID string
+
+	// Where the reference was found, one of the ToolReferenceSource constants.
+	Source string
+
+	// ID of the trigger or shard definition holding the reference, if any.
+	ID string
+}
+
+// InstanceID returns the service instance ID of the tool integration
+func (node *ToolNode) InstanceID() string {
+	if node.Service == nil || node.Service.InstanceID == nil {
+		return ""
+	}
+	return *node.Service.InstanceID
+}
+
+// IsPipeline returns true if the tool integration is a delivery pipeline
+func (node *ToolNode) IsPipeline() bool {
+	return node.Service != nil && node.Service.ServiceID != nil && *node.Service.ServiceID == pipelineServiceID
+}
+
+// IsTektonPipeline returns true if the tool integration is a Tekton delivery pipeline
+func (node *ToolNode) IsTektonPipeline() bool {
+	if !node.IsPipeline() {
+		return false
+	}
+	pipelineType, _ := node.Service.Parameters["type"].(string)
+	return pipelineType == tektonPipelineType
+}
+
+// Pipelines returns the IDs of the pipelines referencing the tool integration
+func (node *ToolNode) Pipelines() []string {
+	seen := make(map[string]bool)
+	pipelines := []string{}
+	for _, ref := range node.References {
+		if !seen[ref.PipelineID] {
+			seen[ref.PipelineID] = true
+			pipelines = append(pipelines, ref.PipelineID)
+		}
+	}
+	return pipelines
+}
+
+// IsReferenced returns true if at least one pipeline references the tool integration
+func (node *ToolNode) IsReferenced() bool {
+	return len(node.References) > 0
+}
+
+// IsShared returns true if more than one pipeline references the tool integration
+func (node *ToolNode) IsShared() bool {
+	return len(node.Pipelines()) > 1
+}
+
+// IsOrphaned returns true if the tool integration is not a pipeline and no pipeline references it
+func (node *ToolNode) IsOrphaned() bool {
+	return !node.IsPipeline() && !node.IsReferenced()
+}
+
+// BuildToolchainGraph : Build the dependency graph of a toolchain
+// The graph links every tool integration in toolchain.Services to the pipeline inputs and triggers referencing it
+// through ServiceInstanceID, and to the shard repositories of the pipeline definitions pointing to its repository.
+// Definitions are matched to pipelines through their PipelineID.
+func BuildToolchainGraph(toolchain *Toolchain, pipelines []TektonPipeline, definitions []GetTektonPipelineDefinitionResponse) *ToolchainGraph {
+	graph := &ToolchainGraph{
+		Toolchain:   toolchain,
+		Pipelines:   pipelines,
+		Definitions: definitions,
+	}
+	if toolchain == nil {
+		return graph
+	}
+
+	byInstanceID := make(map[string]int)
+	byRepoURL := make(map[string]int)
+	for i := range toolchain.Services {
+		service := &toolchain.Services[i]
+		graph.Tools = append(graph.Tools, ToolNode{Service: service})
+		if service.InstanceID != nil {
+			byInstanceID[*service.InstanceID] = i
+		}
+		if repoURL, ok := service.Parameters["repo_url"].(string); ok && repoURL != "" {
+			byRepoURL[NormalizeRepoURL(repoURL)] = i
+		}
+	}
+
+	addReference := func(index int, ref ToolReference) {
+		node := &graph.Tools[index]
+		for _, existing := range node.References {
+			if existing == ref {
+				return
+			}
+		}
+		node.References = append(node.References, ref)
+	}
+
+	for _, pipeline := range pipelines {
+		if pipeline.ID == nil {
+			continue
+		}
+		for _, input := range pipeline.Inputs {
+			if input.ServiceInstanceID == nil {
+				continue
+			}
+			if index, ok := byInstanceID[*input.ServiceInstanceID]; ok {
+				ref := ToolReference{PipelineID: *pipeline.ID, Source: ToolReferenceSourceInputConst}
+				if input.ShardDefinitionID != nil {
+					ref.ID = *input.ShardDefinitionID
+				}
+				addReference(index, ref)
+			}
+		}
+		for _, trigger := range pipeline.Triggers {
+			if trigger.ServiceInstanceID == nil {
+				continue
+			}
+			if index, ok := byInstanceID[*trigger.ServiceInstanceID]; ok {
+				ref := ToolReference{PipelineID: *pipeline.ID, Source: ToolReferenceSourceTriggerConst}
+				if trigger.ID != nil {
+					ref.ID = *trigger.ID
+				}
+				addReference(index, ref)
+			}
+		}
+	}
+
+	for _, definition := range definitions {
+		if definition.PipelineID == nil {
+			continue
+		}
+		for _, shardRepo := range definition.ShardRepos {
+			if shardRepo.RepoURL == nil {
+				continue
+			}
+			if index, ok := byRepoURL[NormalizeRepoURL(*shardRepo.RepoURL)]; ok {
+				ref := ToolReference{PipelineID: *definition.PipelineID, Source: ToolReferenceSourceShardRepoConst}
+				if shardRepo.ShardDefinitionID != nil {
+					ref.ID = *shardRepo.ShardDefinitionID
+				}
+				addReference(index, ref)
+			}
+		}
+	}
+
+	return graph
+}
+
+// Tool returns the node of the tool integration with the specified service instance ID, or nil if there is none
+func (graph *ToolchainGraph) Tool(instanceID string) *ToolNode {
+	for i := range graph.Tools {
+		if graph.Tools[i].InstanceID() == instanceID {
+			return &graph.Tools[i]
+		}
+	}
+	return nil
+}
+
+// repoURL returns the repository URL of a pipeline input or trigger, the repo_url parameter of the tool integration
+// it references if it has no URL of its own. A nil graph resolves no tool integration.
+func (graph *ToolchainGraph) repoURL(url *string, serviceInstanceID *string) string {
+	if resolved := stringValue(url); resolved != "" {
+		return resolved
+	}
+	if graph == nil || serviceInstanceID == nil {
+		return ""
+	}
+	if node := graph.Tool(*serviceInstanceID); node != nil {
+		if resolved, ok := node.Service.Parameters["repo_url"].(string); ok {
+			return resolved
+		}
+	}
+	return ""
+}
+
+// Referenced returns the tool integrations referenced by at least one pipeline
+func (graph *ToolchainGraph) Referenced() []ToolNode {
+	return graph.filter((*ToolNode).IsReferenced)
+}
+
+// Shared returns the tool integrations referenced by more than one pipeline
+func (graph *ToolchainGraph) Shared() []ToolNode {
+	return graph.filter((*ToolNode).IsShared)
+}
+
+// Orphaned returns the tool integrations that are neither pipelines nor referenced by any pipeline
+func (graph *ToolchainGraph) Orphaned() []ToolNode {
+	return graph.filter((*ToolNode).IsOrphaned)
+}
+
+func (graph *ToolchainGraph) filter(predicate func(*ToolNode) bool) []ToolNode {
+	nodes := []ToolNode{}
+	for i := range graph.Tools {
+		if predicate(&graph.Tools[i]) {
+			nodes = append(nodes, graph.Tools[i])
+		}
+	}
+	return nodes
+}
+
+// Print writes a human-readable report of the referenced, shared and orphaned tool integrations to w
+func (graph *ToolchainGraph) Print(w io.Writer) error {
+	name := ""
+	if graph.Toolchain != nil && graph.Toolchain.Name != nil {
+		name = *graph.Toolchain.Name
+	}
+	if _, err := fmt.Fprintf(w, "Toolchain %s\n", name); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title string
+		nodes []ToolNode
+	}{
+		{"Referenced", graph.Referenced()},
+		{"Shared", graph.Shared()},
+		{"Orphaned", graph.Orphaned()},
+	}
+	for _, section := range sections {
+		if _, err := fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.nodes)); err != nil {
+			return err
+		}
+		for _, node := range section.nodes {
+			line := fmt.Sprintf("  %s %s", describeTool(node.Service), node.InstanceID())
+			if pipelines := node.Pipelines(); len(pipelines) > 0 {
+				line += fmt.Sprintf(" <- %s", strings.Join(pipelines, ", "))
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describeTool returns the service ID and, when available, the display name of a tool integration.
+func describeTool(service *Service) string {
+	description := ""
+	if service.ServiceID != nil {
+		description = *service.ServiceID
+	}
+	if service.ToolchainBinding != nil && service.ToolchainBinding.Name != nil && *service.ToolchainBinding.Name != "" {
+		description += fmt.Sprintf(" (%s)", *service.ToolchainBinding.Name)
+	}
+	return description
+}
+
+// DeletionStep : A single deletion performed when deleting a toolchain in dependency order
+type DeletionStep struct {
+	// One of the DeletionStepKind constants.
+	Kind string
+
+	// Service instance ID of the tool integration, or the toolchain GUID.
+	ID string
+
+	ServiceID string
+
+	// Error returned when the step was executed, nil if it succeeded or was not executed.
+	Error error
+
+	// Whether the step was executed.
+	Done bool
+}
+
+// DeletionPlan returns the steps needed to delete the toolchain in dependency order
+// Pipelines are deleted first since they hold the references, then the remaining tool integrations, most referenced
+// first, and finally the toolchain itself.
+func (graph *ToolchainGraph) DeletionPlan() []DeletionStep {
+	pipelines := []ToolNode{}
+	tools := []ToolNode{}
+	for _, node := range graph.Tools {
+		if node.IsPipeline() {
+			pipelines = append(pipelines, node)
+		} else {
+			tools = append(tools, node)
+		}
+	}
+	sort.SliceStable(tools, func(i, j int) bool {
+		return len(tools[i].Pipelines()) > len(tools[j].Pipelines())
+	})
+
+	plan := []DeletionStep{}
+	for _, node := range append(pipelines, tools...) {
+		kind := DeletionStepKindToolConst
+		if node.IsPipeline() {
+			kind = DeletionStepKindPipelineConst
+		}
+		step := DeletionStep{Kind: kind, ID: node.InstanceID()}
+		if node.Service.ServiceID != nil {
+			step.ServiceID = *node.Service.ServiceID
+		}
+		plan = append(plan, step)
+	}
+	if graph.Toolchain != nil && graph.Toolchain.ToolchainGUID != nil {
+		plan = append(plan, DeletionStep{Kind: DeletionStepKindToolchainConst, ID: *graph.Toolchain.ToolchainGUID})
+	}
+	return plan
+}
+
+// GetToolchainGraph : Build the dependency graph of a toolchain
+// Fetches the toolchain, every Tekton pipeline among its tool integrations and their definitions, and links them
+// with BuildToolchainGraph. Pipelines without a definition are kept in the graph.
+func (openToolchain *OpenToolchainV1) GetToolchainGraph(getToolchainGraphOptions *GetToolchainGraphOptions) (result *ToolchainGraph, err error) {
+	return openToolchain.GetToolchainGraphWithContext(context.Background(), getToolchainGraphOptions)
+}
+
+// GetToolchainGraphWithContext is an alternate form of the GetToolchainGraph method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) GetToolchainGraphWithContext(ctx context.Context, getToolchainGraphOptions *GetToolchainGraphOptions) (result *ToolchainGraph, err error) {
+	err = core.ValidateNotNil(getToolchainGraphOptions, "getToolchainGraphOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(getToolchainGraphOptions, "getToolchainGraphOptions")
+	if err != nil {
+		return
+	}
+
+	getToolchainOptions := openToolchain.NewGetToolchainOptions(*getToolchainGraphOptions.Region, *getToolchainGraphOptions.GUID)
+	getToolchainOptions.SetIncludes(NewToolchainIncludes().WithFields().WithServices())
+	getToolchainOptions.SetHeaders(getToolchainGraphOptions.Headers)
+	toolchainResponse, _, err := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+	if err != nil {
+		return
+	}
+	if toolchainResponse == nil || len(toolchainResponse.Items) == 0 {
+		err = fmt.Errorf("toolchain %s not found", *getToolchainGraphOptions.GUID)
+		return
+	}
+	toolchain := &toolchainResponse.Items[0]
+
+	pipelines := []TektonPipeline{}
+	definitions := []GetTektonPipelineDefinitionResponse{}
+	for i := range toolchain.Services {
+		node := ToolNode{Service: &toolchain.Services[i]}
+		if !node.IsTektonPipeline() || node.InstanceID() == "" {
+			continue
+		}
+
+		getTektonPipelineOptions := openToolchain.NewGetTektonPipelineOptions(node.InstanceID(), *getToolchainGraphOptions.Region)
+		getTektonPipelineOptions.SetHeaders(getToolchainGraphOptions.Headers)
+		pipeline, _, pipelineErr := openToolchain.GetTektonPipelineWithContext(ctx, getTektonPipelineOptions)
+		if pipelineErr != nil {
+			err = fmt.Errorf("error getting pipeline %s: %s", node.InstanceID(), pipelineErr.Error())
+			return
+		}
+		if pipeline != nil {
+			pipelines = append(pipelines, *pipeline)
+		}
+
+		getTektonPipelineDefinitionOptions := openToolchain.NewGetTektonPipelineDefinitionOptions(node.InstanceID(), *getToolchainGraphOptions.Region, *getToolchainGraphOptions.EnvID)
+		getTektonPipelineDefinitionOptions.SetHeaders(getToolchainGraphOptions.Headers)
+		definition, definitionResponse, definitionErr := openToolchain.GetTektonPipelineDefinitionWithContext(ctx, getTektonPipelineDefinitionOptions)
+		if definitionErr != nil {
+			// A pipeline without a definition yet is answered with a 404
+			if definitionResponse != nil && definitionResponse.StatusCode == http.StatusNotFound {
+				continue
+			}
+			err = fmt.Errorf("error getting definition of pipeline %s: %s", node.InstanceID(), definitionErr.Error())
+			return
+		}
+		if definition != nil {
+			definitions = append(definitions, *definition)
+		}
+	}
+
+	result = BuildToolchainGraph(toolchain, pipelines, definitions)
+	return
+}
+
+// DeleteToolchainGraph : Delete a toolchain and its tool integrations in dependency order
+// Pipelines are deleted first, then the remaining tool integrations and finally the toolchain. Deletion stops at the
+// first failing step. In dry-run mode the plan is returned without deleting anything.
+func (openToolchain *OpenToolchainV1) DeleteToolchainGraph(deleteToolchainGraphOptions *DeleteToolchainGraphOptions) (result []DeletionStep, err error) {
+	return openToolchain.DeleteToolchainGraphWithContext(context.Background(), deleteToolchainGraphOptions)
+}
+
+// DeleteToolchainGraphWithContext is an alternate form of the DeleteToolchainGraph method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) DeleteToolchainGraphWithContext(ctx context.Context, deleteToolchainGraphOptions *DeleteToolchainGraphOptions) (result []DeletionStep, err error) {
+	err = core.ValidateNotNil(deleteToolchainGraphOptions, "deleteToolchainGraphOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(deleteToolchainGraphOptions, "deleteToolchainGraphOptions")
+	if err != nil {
+		return
+	}
+
+	graph := deleteToolchainGraphOptions.Graph
+	if graph == nil {
+		getToolchainGraphOptions := openToolchain.NewGetToolchainGraphOptions(*deleteToolchainGraphOptions.Region, *deleteToolchainGraphOptions.GUID, *deleteToolchainGraphOptions.EnvID)
+		getToolchainGraphOptions.SetHeaders(deleteToolchainGraphOptions.Headers)
+		graph, err = openToolchain.GetToolchainGraphWithContext(ctx, getToolchainGraphOptions)
+		if err != nil {
+			return
+		}
+	} else if graph.Toolchain == nil || stringValue(graph.Toolchain.ToolchainGUID) != *deleteToolchainGraphOptions.GUID {
+		// The tool integrations are deleted from GUID, the graph must not name another toolchain
+		err = fmt.Errorf("graph is not the graph of toolchain %s", *deleteToolchainGraphOptions.GUID)
+		return
+	}
+
+	result = graph.DeletionPlan()
+	if deleteToolchainGraphOptions.DryRun != nil && *deleteToolchainGraphOptions.DryRun {
+		return
+	}
+
+	for i := range result {
+		step := &result[i]
+		switch step.Kind {
+		case DeletionStepKindToolchainConst:
+			deleteToolchainOptions := openToolchain.NewDeleteToolchainOptions(*deleteToolchainGraphOptions.Region, step.ID)
+			deleteToolchainOptions.SetHeaders(deleteToolchainGraphOptions.Headers)
+			_, step.Error = openToolchain.DeleteToolchainWithContext(ctx, deleteToolchainOptions)
+		default:
+			deleteServiceInstanceOptions := openToolchain.NewDeleteServiceInstanceOptions(step.ID, *deleteToolchainGraphOptions.EnvID)
+			deleteServiceInstanceOptions.SetToolchainID(*deleteToolchainGraphOptions.GUID)
+			deleteServiceInstanceOptions.SetHeaders(deleteToolchainGraphOptions.Headers)
+			_, step.Error = openToolchain.DeleteServiceInstanceWithContext(ctx, deleteServiceInstanceOptions)
+		}
+		step.Done = true
+		if step.Error != nil {
+			err = fmt.Errorf("error deleting %s %s: %s", step.Kind, step.ID, step.Error.Error())
+			return
+		}
+	}
+
+	return
+}
+
+// GetToolchainGraphOptions : The GetToolchainGraph options.
+type GetToolchainGraphOptions struct {
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// GUID of the toolchain.
+	GUID *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewGetToolchainGraphOptions : Instantiate GetToolchainGraphOptions
+func (*OpenToolchainV1) NewGetToolchainGraphOptions(region string, guid string, envID string) *GetToolchainGraphOptions {
+	return &GetToolchainGraphOptions{
+		Region: core.StringPtr(region),
+		GUID:   core.StringPtr(guid),
+		EnvID:  core.StringPtr(envID),
+	}
+}
+
+// SetRegion : Allow user to set Region
+func (options *GetToolchainGraphOptions) SetRegion(region string) *GetToolchainGraphOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetGUID : Allow user to set GUID
+func (options *GetToolchainGraphOptions) SetGUID(guid string) *GetToolchainGraphOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *GetToolchainGraphOptions) SetEnvID(envID string) *GetToolchainGraphOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *GetToolchainGraphOptions) SetHeaders(param map[string]string) *GetToolchainGraphOptions {
+	options.Headers = param
+	return options
+}
+
+// DeleteToolchainGraphOptions : The DeleteToolchainGraph options.
+type DeleteToolchainGraphOptions struct {
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// GUID of the toolchain.
+	GUID *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// Return the deletion plan without deleting anything.
+	DryRun *bool
+
+	// Previously built graph of the toolchain, whose toolchain GUID must be GUID. Fetched with GetToolchainGraph when
+	// not set.
+	Graph *ToolchainGraph
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewDeleteToolchainGraphOptions : Instantiate DeleteToolchainGraphOptions
+func (*OpenToolchainV1) NewDeleteToolchainGraphOptions(region string, guid string, envID string) *DeleteToolchainGraphOptions {
+	return &DeleteToolchainGraphOptions{
+		Region: core.StringPtr(region),
+		GUID:   core.StringPtr(guid),
+		EnvID:  core.StringPtr(envID),
+	}
+}
+
+// SetRegion : Allow user to set Region
+func (options *DeleteToolchainGraphOptions) SetRegion(region string) *DeleteToolchainGraphOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetGUID : Allow user to set GUID
+func (options *DeleteToolchainGraphOptions) SetGUID(guid string) *DeleteToolchainGraphOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *DeleteToolchainGraphOptions) SetEnvID(envID string) *DeleteToolchainGraphOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (options *DeleteToolchainGraphOptions) SetDryRun(dryRun bool) *DeleteToolchainGraphOptions {
+	options.DryRun = core.BoolPtr(dryRun)
+	return options
+}
+
+// SetGraph : Allow user to set Graph
+func (options *DeleteToolchainGraphOptions) SetGraph(graph *ToolchainGraph) *DeleteToolchainGraphOptions {
+	options.Graph = graph
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *DeleteToolchainGraphOptions) SetHeaders(param map[string]string) *DeleteToolchainGraphOptions {
+	options.Headers = param
+	return options
+}
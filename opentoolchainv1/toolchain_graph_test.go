@@ -0,0 +1,259 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const graphToolchainResponse = `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "my-toolchain", "services": [
+	{"service_id": "pipeline", "instance_id": "pl1", "parameters": {"type": "tekton"}},
+	{"service_id": "pipeline", "instance_id": "pl2", "parameters": {"type": "tekton"}},
+	{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"repo_url": "https://github.com/org/app.git"}},
+	{"service_id": "githubconsolidated", "instance_id": "repo2", "parameters": {"repo_url": "https://github.com/org/defs"}},
+	{"service_id": "slack", "instance_id": "slack1"}
+]}]}`
+
+var _ = Describe(`ToolchainGraph`, func() {
+	var testServer *httptest.Server
+
+	pipeline1 := opentoolchainv1.TektonPipeline{
+		ID: core.StringPtr("pl1"),
+		Inputs: []opentoolchainv1.TektonPipelineInput{
+			{ServiceInstanceID: core.StringPtr("repo2"), ShardDefinitionID: core.StringPtr("shard1")},
+		},
+		Triggers: []opentoolchainv1.TektonPipelineTrigger{
+			{ID: core.StringPtr("t1"), ServiceInstanceID: core.StringPtr("repo1")},
+		},
+	}
+	pipeline2 := opentoolchainv1.TektonPipeline{
+		ID: core.StringPtr("pl2"),
+		Triggers: []opentoolchainv1.TektonPipelineTrigger{
+			{ID: core.StringPtr("t2"), ServiceInstanceID: core.StringPtr("repo1")},
+		},
+	}
+	definition1 := opentoolchainv1.GetTektonPipelineDefinitionResponse{
+		PipelineID: core.StringPtr("pl1"),
+		ShardRepos: []opentoolchainv1.ShardRepo{
+			{ShardDefinitionID: core.StringPtr("shard1"), RepoURL: core.StringPtr("https://github.com/org/defs.git")},
+		},
+	}
+
+	Describe(`BuildToolchainGraph(toolchain *Toolchain, pipelines []TektonPipeline, definitions []GetTektonPipelineDefinitionResponse)`, func() {
+		var graph *opentoolchainv1.ToolchainGraph
+		BeforeEach(func() {
+			toolchain := &opentoolchainv1.Toolchain{
+				ToolchainGUID: core.StringPtr("tc1"),
+				Name:          core.StringPtr("my-toolchain"),
+				Services: []opentoolchainv1.Service{
+					{ServiceID: core.StringPtr("pipeline"), InstanceID: core.StringPtr("pl1"), Parameters: map[string]interface{}{"type": "tekton"}},
+					{ServiceID: core.StringPtr("pipeline"), InstanceID: core.StringPtr("pl2"), Parameters: map[string]interface{}{"type": "tekton"}},
+					{ServiceID: core.StringPtr("githubconsolidated"), InstanceID: core.StringPtr("repo1")},
+					{ServiceID: core.StringPtr("githubconsolidated"), InstanceID: core.StringPtr("repo2"), Parameters: map[string]interface{}{"repo_url": "https://github.com/org/defs"}},
+					{ServiceID: core.StringPtr("slack"), InstanceID: core.StringPtr("slack1")},
+				},
+			}
+			graph = opentoolchainv1.BuildToolchainGraph(toolchain, []opentoolchainv1.TektonPipeline{pipeline1, pipeline2}, []opentoolchainv1.GetTektonPipelineDefinitionResponse{definition1})
+		})
+		It(`Classify referenced, shared and orphaned tools`, func() {
+			Expect(graph.Tools).To(HaveLen(5))
+			Expect(toolIDs(graph.Referenced())).To(Equal([]string{"repo1", "repo2"}))
+			Expect(toolIDs(graph.Shared())).To(Equal([]string{"repo1"}))
+			Expect(toolIDs(graph.Orphaned())).To(Equal([]string{"slack1"}))
+
+			repo2 := graph.Tool("repo2")
+			Expect(repo2).ToNot(BeNil())
+			Expect(repo2.References).To(ConsistOf(
+				opentoolchainv1.ToolReference{PipelineID: "pl1", Source: opentoolchainv1.ToolReferenceSourceInputConst, ID: "shard1"},
+				opentoolchainv1.ToolReference{PipelineID: "pl1", Source: opentoolchainv1.ToolReferenceSourceShardRepoConst, ID: "shard1"},
+			))
+			Expect(repo2.Pipelines()).To(Equal([]string{"pl1"}))
+			Expect(graph.Tool("missing")).To(BeNil())
+		})
+		It(`Print a report`, func() {
+			var buf bytes.Buffer
+			Expect(graph.Print(&buf)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("Shared (1):\n  githubconsolidated repo1 <- pl1, pl2\n"))
+			Expect(buf.String()).To(ContainSubstring("Orphaned (1):\n  slack slack1\n"))
+		})
+		It(`Plan deletion in dependency order`, func() {
+			plan := graph.DeletionPlan()
+			ids := []string{}
+			for _, step := range plan {
+				ids = append(ids, step.ID)
+			}
+			Expect(ids).To(Equal([]string{"pl1", "pl2", "repo1", "repo2", "slack1", "tc1"}))
+			Expect(plan[0].Kind).To(Equal(opentoolchainv1.DeletionStepKindPipelineConst))
+			Expect(plan[2].Kind).To(Equal(opentoolchainv1.DeletionStepKindToolConst))
+			Expect(plan[5].Kind).To(Equal(opentoolchainv1.DeletionStepKindToolchainConst))
+		})
+		It(`Handle a nil toolchain`, func() {
+			graph := opentoolchainv1.BuildToolchainGraph(nil, nil, nil)
+			Expect(graph.Tools).To(BeEmpty())
+			Expect(graph.DeletionPlan()).To(BeEmpty())
+		})
+	})
+
+	Describe(`DeleteToolchainGraph(deleteToolchainGraphOptions *DeleteToolchainGraphOptions)`, func() {
+		var mutex sync.Mutex
+		var deleted []string
+		var definitionStatus int
+		BeforeEach(func() {
+			deleted = []string{}
+			definitionStatus = 200
+			testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				path := req.URL.EscapedPath()
+				res.Header().Set("Content-type", "application/json")
+				switch {
+				case req.Method == "DELETE":
+					mutex.Lock()
+					deleted = append(deleted, path[strings.LastIndex(path, "/")+1:])
+					mutex.Unlock()
+					res.WriteHeader(204)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+					Expect(req.URL.Query()["include"]).To(Equal([]string{"fields,services"}))
+					res.WriteHeader(200)
+					fmt.Fprint(res, graphToolchainResponse)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1/definition":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"pipelineId": "pl1", "id": "d1", "shardRepos": [{"shardDefinitionId": "shard1", "repoUrl": "https://github.com/org/defs"}]}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl2/definition":
+					res.WriteHeader(definitionStatus)
+					fmt.Fprint(res, `{"pipelineId": "pl2", "id": "d2"}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl1":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl1", "name": "pl1", "toolchainId": "tc1", "envProperties": [], "triggers": [{"id": "t1", "eventListener": "l", "type": "scm", "serviceInstanceId": "repo1"}]}`)
+				case path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl2":
+					res.WriteHeader(200)
+					fmt.Fprint(res, `{"id": "pl2", "name": "pl2", "toolchainId": "tc1", "envProperties": [], "triggers": [{"id": "t2", "eventListener": "l", "type": "scm", "serviceInstanceId": "repo1"}]}`)
+				default:
+					res.WriteHeader(404)
+				}
+			}))
+		})
+		It(`Invoke DeleteToolchainGraph in dry-run mode`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			graph, err := openToolchainService.GetToolchainGraph(openToolchainService.NewGetToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south"))
+			Expect(err).To(BeNil())
+			Expect(toolIDs(graph.Shared())).To(Equal([]string{"repo1"}))
+			Expect(toolIDs(graph.Orphaned())).To(Equal([]string{"slack1"}))
+			Expect(graph.Tool("repo2").IsReferenced()).To(BeTrue())
+
+			deleteToolchainGraphOptionsModel := openToolchainService.NewDeleteToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south")
+			deleteToolchainGraphOptionsModel.SetDryRun(true)
+			steps, err := openToolchainService.DeleteToolchainGraph(deleteToolchainGraphOptionsModel)
+			Expect(err).To(BeNil())
+			Expect(steps).To(HaveLen(6))
+			for _, step := range steps {
+				Expect(step.Done).To(BeFalse())
+			}
+			Expect(deleted).To(BeEmpty())
+		})
+		It(`Invoke GetToolchainGraph with a pipeline without definition`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			definitionStatus = 404
+			graph, err := openToolchainService.GetToolchainGraph(openToolchainService.NewGetToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south"))
+			Expect(err).To(BeNil())
+			Expect(graph.Pipelines).To(HaveLen(2))
+			Expect(graph.Definitions).To(HaveLen(1))
+			Expect(*graph.Definitions[0].PipelineID).To(Equal("pl1"))
+
+			// Other errors still fail the graph
+			definitionStatus = 500
+			graph, err = openToolchainService.GetToolchainGraph(openToolchainService.NewGetToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south"))
+			Expect(err).ToNot(BeNil())
+			Expect(graph).To(BeNil())
+		})
+		It(`Invoke DeleteToolchainGraph successfully`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			steps, err := openToolchainService.DeleteToolchainGraph(openToolchainService.NewDeleteToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south"))
+			Expect(err).To(BeNil())
+			Expect(steps).To(HaveLen(6))
+			Expect(deleted).To(Equal([]string{"pl1", "pl2", "repo1", "repo2", "slack1", "tc1"}))
+		})
+		It(`Invoke DeleteToolchainGraph with error: Operation validation and request error`, func() {
+			openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+				URL:           testServer.URL,
+				Authenticator: &core.NoAuthAuthenticator{},
+			})
+			Expect(serviceErr).To(BeNil())
+
+			steps, err := openToolchainService.DeleteToolchainGraph(nil)
+			Expect(err).ToNot(BeNil())
+			Expect(steps).To(BeNil())
+
+			steps, err = openToolchainService.DeleteToolchainGraph(new(opentoolchainv1.DeleteToolchainGraphOptions))
+			Expect(err).ToNot(BeNil())
+			Expect(steps).To(BeNil())
+
+			steps, err = openToolchainService.DeleteToolchainGraph(openToolchainService.NewDeleteToolchainGraphOptions("us-south", "missing", "ibm:yp:us-south"))
+			Expect(err).ToNot(BeNil())
+			Expect(steps).To(BeNil())
+
+			// A graph of another toolchain is rejected before deleting anything
+			graph, err := openToolchainService.GetToolchainGraph(openToolchainService.NewGetToolchainGraphOptions("us-south", "tc1", "ibm:yp:us-south"))
+			Expect(err).To(BeNil())
+			deleteToolchainGraphOptionsModel := openToolchainService.NewDeleteToolchainGraphOptions("us-south", "tc2", "ibm:yp:us-south")
+			deleteToolchainGraphOptionsModel.SetGraph(graph)
+			steps, err = openToolchainService.DeleteToolchainGraph(deleteToolchainGraphOptionsModel)
+			Expect(err).To(MatchError("graph is not the graph of toolchain tc2"))
+			Expect(steps).To(BeNil())
+			deleteToolchainGraphOptionsModel.SetGraph(opentoolchainv1.BuildToolchainGraph(nil, nil, nil))
+			_, err = openToolchainService.DeleteToolchainGraph(deleteToolchainGraphOptionsModel)
+			Expect(err).ToNot(BeNil())
+			Expect(deleted).To(BeEmpty())
+		})
+		AfterEach(func() {
+			testServer.Close()
+		})
+	})
+})
+
+func toolIDs(nodes []opentoolchainv1.ToolNode) []string {
+	ids := []string{}
+	for i := range nodes {
+		ids = append(ids, nodes[i].InstanceID())
+	}
+	return ids
+}
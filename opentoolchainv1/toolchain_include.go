@@ -0,0 +1,164 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constants associated with the GetToolchainOptions.Include property.
+// Sections of the toolchain returned by GetToolchain, the toolchain GUID and name are always returned.
+const (
+	// Description, key, CRN, creation and update dates, creator, generator, tags, webhook and region ID
+	ToolchainIncludeFieldsConst = "fields"
+	// Resource group or organization of the toolchain
+	ToolchainIncludeContainerConst = "container"
+	// Template the toolchain was created from
+	ToolchainIncludeTemplateConst = "template"
+	// Tool integrations of the toolchain
+	ToolchainIncludeServicesConst = "services"
+)
+
+// toolchainIncludeSections lists the sections supported by GetToolchain, in the order they are requested.
+var toolchainIncludeSections = []string{
+	ToolchainIncludeFieldsConst,
+	ToolchainIncludeContainerConst,
+	ToolchainIncludeTemplateConst,
+	ToolchainIncludeServicesConst,
+}
+
+// ToolchainIncludes : Set of toolchain sections to include in GetToolchain responses
+type ToolchainIncludes struct {
+	sections map[string]bool
+}
+
+// NewToolchainIncludes : Instantiate ToolchainIncludes with the given sections
+func NewToolchainIncludes(sections ...string) *ToolchainIncludes {
+	includes := &ToolchainIncludes{sections: map[string]bool{}}
+	for _, section := range sections {
+		includes.Add(section)
+	}
+	return includes
+}
+
+// AllToolchainIncludes : Instantiate ToolchainIncludes with every supported section
+func AllToolchainIncludes() *ToolchainIncludes {
+	return NewToolchainIncludes(toolchainIncludeSections...)
+}
+
+// ParseToolchainIncludes parses a comma-separated list of sections, returning an error for unsupported sections
+func ParseToolchainIncludes(include string) (*ToolchainIncludes, error) {
+	includes := NewToolchainIncludes()
+	for _, section := range strings.Split(include, ",") {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+		if !isToolchainIncludeSection(section) {
+			return nil, fmt.Errorf("unsupported toolchain section %q", section)
+		}
+		includes.Add(section)
+	}
+	return includes, nil
+}
+
+// isToolchainIncludeSection returns true if the section is supported by GetToolchain.
+func isToolchainIncludeSection(section string) bool {
+	for _, supported := range toolchainIncludeSections {
+		if section == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Add : Include a section
+func (includes *ToolchainIncludes) Add(section string) *ToolchainIncludes {
+	includes.sections[section] = true
+	return includes
+}
+
+// WithFields : Include the fields section
+func (includes *ToolchainIncludes) WithFields() *ToolchainIncludes {
+	return includes.Add(ToolchainIncludeFieldsConst)
+}
+
+// WithContainer : Include the container section
+func (includes *ToolchainIncludes) WithContainer() *ToolchainIncludes {
+	return includes.Add(ToolchainIncludeContainerConst)
+}
+
+// WithTemplate : Include the template section
+func (includes *ToolchainIncludes) WithTemplate() *ToolchainIncludes {
+	return includes.Add(ToolchainIncludeTemplateConst)
+}
+
+// WithServices : Include the services section
+func (includes *ToolchainIncludes) WithServices() *ToolchainIncludes {
+	return includes.Add(ToolchainIncludeServicesConst)
+}
+
+// Has returns true if the section is included
+func (includes *ToolchainIncludes) Has(section string) bool {
+	return includes != nil && includes.sections[section]
+}
+
+// Sections returns the included sections, supported sections first
+func (includes *ToolchainIncludes) Sections() []string {
+	sections := []string{}
+	if includes == nil {
+		return sections
+	}
+	for _, section := range toolchainIncludeSections {
+		if includes.sections[section] {
+			sections = append(sections, section)
+		}
+	}
+	var others []string
+	for section := range includes.sections {
+		if !isToolchainIncludeSection(section) {
+			others = append(others, section)
+		}
+	}
+	sort.Strings(others)
+	return append(sections, others...)
+}
+
+// String returns the comma-separated list of included sections
+func (includes *ToolchainIncludes) String() string {
+	return strings.Join(includes.Sections(), ",")
+}
+
+// SetIncludes : Allow user to set Include from a set of sections
+func (options *GetToolchainOptions) SetIncludes(includes *ToolchainIncludes) *GetToolchainOptions {
+	return options.SetInclude(includes.String())
+}
+
+// Includes returns the sections requested by the options
+func (options *GetToolchainOptions) Includes() *ToolchainIncludes {
+	includes := NewToolchainIncludes()
+	if options.Include != nil {
+		for _, section := range strings.Split(*options.Include, ",") {
+			if section = strings.TrimSpace(section); section != "" {
+				includes.Add(section)
+			}
+		}
+	}
+	return includes
+}
@@ -0,0 +1,77 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ToolchainIncludes`, func() {
+	It(`Invoke ToolchainIncludes builder successfully`, func() {
+		includes := opentoolchainv1.NewToolchainIncludes().WithServices().WithFields().WithServices()
+		Expect(includes.String()).To(Equal("fields,services"))
+		Expect(includes.Has(opentoolchainv1.ToolchainIncludeServicesConst)).To(BeTrue())
+		Expect(includes.Has(opentoolchainv1.ToolchainIncludeTemplateConst)).To(BeFalse())
+
+		// Sections unknown to the SDK are passed through after the supported ones
+		includes.Add("preview").WithContainer()
+		Expect(includes.Sections()).To(Equal([]string{"fields", "container", "services", "preview"}))
+
+		Expect(opentoolchainv1.AllToolchainIncludes().String()).To(Equal("fields,container,template,services"))
+		Expect(opentoolchainv1.NewToolchainIncludes().String()).To(BeEmpty())
+	})
+	It(`Invoke ParseToolchainIncludes successfully`, func() {
+		includes, err := opentoolchainv1.ParseToolchainIncludes("services, template,")
+		Expect(err).To(BeNil())
+		Expect(includes.String()).To(Equal("template,services"))
+
+		_, err = opentoolchainv1.ParseToolchainIncludes("services,preview")
+		Expect(err).To(MatchError(`unsupported toolchain section "preview"`))
+	})
+	It(`Invoke GetToolchain with includes successfully`, func() {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.URL.Query()["include"]).To(Equal([]string{"container,services"}))
+			res.Header().Set("Content-type", "application/json")
+			res.WriteHeader(200)
+			fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain", "container": {"guid": "rg1", "type": "resource_group_id"}, "services": []}]}`)
+		}))
+		defer testServer.Close()
+
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		getToolchainOptionsModel := openToolchainService.NewGetToolchainOptions("us-south", "tc1")
+		getToolchainOptionsModel.SetIncludes(opentoolchainv1.NewToolchainIncludes().WithServices().WithContainer())
+		Expect(getToolchainOptionsModel.Includes().Has(opentoolchainv1.ToolchainIncludeContainerConst)).To(BeTrue())
+		result, _, err := openToolchainService.GetToolchain(getToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(*result.Items[0].Container.GUID).To(Equal("rg1"))
+		Expect(result.Items[0].Template).To(BeNil())
+	})
+})
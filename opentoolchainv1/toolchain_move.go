@@ -0,0 +1,429 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// unreadableToolParameters are the tool integration parameters holding secrets, which cannot be read back to
+// recreate the tool integration.
+var unreadableToolParameters = map[string]bool{
+	"api_key":     true,
+	"api_token":   true,
+	"service_key": true,
+}
+
+// createServiceInstanceParameters are the parameters accepted by CreateServiceInstance.
+var createServiceInstanceParameters = func() map[string]bool {
+	parameters := map[string]bool{}
+	parametersType := reflect.TypeOf(CreateServiceInstanceParamsParameters{})
+	for i := 0; i < parametersType.NumField(); i++ {
+		name := strings.Split(parametersType.Field(i).Tag.Get("json"), ",")[0]
+		parameters[name] = true
+	}
+	return parameters
+}()
+
+// MoveToolchainResult : The result of MoveToolchain
+type MoveToolchainResult struct {
+	// GUID of the moved toolchain.
+	SourceGUID string
+
+	// GUID of the toolchain created in the target resource group. Empty in dry-run mode.
+	TargetGUID string
+
+	// Tool integrations of the moved toolchain, in the order they are listed in the toolchain.
+	Tools []ToolMove
+
+	// Whether the moved toolchain was deleted.
+	SourceDeleted bool
+}
+
+// ToolMove : How a tool integration is moved to the target resource group
+type ToolMove struct {
+	// Tool integration of the moved toolchain.
+	Service *Service
+
+	// Whether the tool integration can be recreated in the target toolchain.
+	Movable bool
+
+	// Why the tool integration cannot be moved.
+	Reason string
+
+	// Service instance ID of the tool integration in the target toolchain.
+	NewInstanceID string
+
+	// Error returned when recreating the tool integration.
+	Error error
+
+	// Whether the tool integration was recreated in the target toolchain.
+	Moved bool
+
+	// SECURE environment properties of a Tekton pipeline that must be set by hand in the target toolchain.
+	SecurePropertiesToSet []string
+
+	parameters *CreateServiceInstanceParamsParameters
+}
+
+// NotMovable returns the tool integrations that cannot be moved
+func (result *MoveToolchainResult) NotMovable() []ToolMove {
+	tools := []ToolMove{}
+	for _, tool := range result.Tools {
+		if !tool.Movable {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Failed returns the movable tool integrations that could not be recreated
+func (result *MoveToolchainResult) Failed() []ToolMove {
+	tools := []ToolMove{}
+	for _, tool := range result.Tools {
+		if tool.Error != nil {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Print writes a human-readable report of the tool integrations that were moved and of those that were not to w
+func (result *MoveToolchainResult) Print(w io.Writer) error {
+	target := result.TargetGUID
+	if target == "" {
+		target = "(dry run)"
+	}
+	if _, err := fmt.Fprintf(w, "Toolchain %s -> %s\n", result.SourceGUID, target); err != nil {
+		return err
+	}
+	for _, tool := range result.Tools {
+		line := fmt.Sprintf("  %s %s", describeTool(tool.Service), stringValue(tool.Service.InstanceID))
+		switch {
+		case !tool.Movable:
+			line += ": cannot move, " + tool.Reason
+		case tool.Error != nil:
+			line += ": failed, " + tool.Error.Error()
+		case tool.Moved:
+			line += " -> " + tool.NewInstanceID
+			if len(tool.SecurePropertiesToSet) > 0 {
+				line += ", set secure properties by hand: " + strings.Join(tool.SecurePropertiesToSet, ", ")
+			}
+		default:
+			line += ": movable"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planToolMove returns how a tool integration is moved.
+func planToolMove(service *Service) ToolMove {
+	tool := ToolMove{Service: service}
+	node := ToolNode{Service: service}
+	if node.IsPipeline() && !node.IsTektonPipeline() {
+		tool.Reason = "the configuration of classic pipelines cannot be copied"
+		return tool
+	}
+
+	secrets := []string{}
+	unsupported := []string{}
+	for name := range service.Parameters {
+		switch {
+		case unreadableToolParameters[name]:
+			secrets = append(secrets, name)
+		case !createServiceInstanceParameters[name]:
+			unsupported = append(unsupported, name)
+		}
+	}
+	sort.Strings(secrets)
+	sort.Strings(unsupported)
+	if len(secrets) > 0 {
+		tool.Reason = fmt.Sprintf("secret parameters cannot be read back: %s", strings.Join(secrets, ", "))
+		return tool
+	}
+	if len(unsupported) > 0 {
+		tool.Reason = fmt.Sprintf("unsupported parameters: %s", strings.Join(unsupported, ", "))
+		return tool
+	}
+
+	data, err := json.Marshal(service.Parameters)
+	if err == nil {
+		tool.parameters = new(CreateServiceInstanceParamsParameters)
+		err = json.Unmarshal(data, tool.parameters)
+	}
+	if err != nil {
+		tool.Reason = fmt.Sprintf("invalid parameters: %s", err.Error())
+		return tool
+	}
+	tool.Movable = true
+	return tool
+}
+
+// MoveToolchain : Move a toolchain to another resource group
+// Resource groups cannot be changed after creation, so the toolchain is recreated in the target resource group from its
+// template and its tool integrations are recreated in the new toolchain with the same parameters, except those the
+// template already created with the same service ID and name, which are kept. Tool integrations holding secrets,
+// classic pipelines and tool integrations with parameters unknown to CreateServiceInstance cannot be moved and are
+// reported with the reason. The configuration of Tekton pipelines is copied with CopyTektonPipelineConfig once the
+// other tool integrations exist; their SECURE properties cannot be copied and are reported to be set by hand. The moved
+// toolchain is only deleted when DeleteSource is set and every tool integration was moved. In dry-run mode the plan is
+// returned without creating anything.
+func (openToolchain *OpenToolchainV1) MoveToolchain(moveToolchainOptions *MoveToolchainOptions) (result *MoveToolchainResult, response *core.DetailedResponse, err error) {
+	return openToolchain.MoveToolchainWithContext(context.Background(), moveToolchainOptions)
+}
+
+// MoveToolchainWithContext is an alternate form of the MoveToolchain method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) MoveToolchainWithContext(ctx context.Context, moveToolchainOptions *MoveToolchainOptions) (result *MoveToolchainResult, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(moveToolchainOptions, "moveToolchainOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(moveToolchainOptions, "moveToolchainOptions")
+	if err != nil {
+		return
+	}
+
+	region := *moveToolchainOptions.Region
+	guid := *moveToolchainOptions.GUID
+	envID := *moveToolchainOptions.EnvID
+	resourceGroupID := *moveToolchainOptions.ResourceGroupID
+	headers := moveToolchainOptions.Headers
+
+	getToolchainOptions := openToolchain.NewGetToolchainOptions(region, guid)
+	getToolchainOptions.SetIncludes(NewToolchainIncludes().WithFields().WithContainer().WithTemplate().WithServices())
+	getToolchainOptions.SetHeaders(headers)
+	toolchainResponse, response, err := openToolchain.GetToolchainWithContext(ctx, getToolchainOptions)
+	if err != nil {
+		return
+	}
+	if toolchainResponse == nil || len(toolchainResponse.Items) == 0 {
+		err = fmt.Errorf("toolchain %s not found", guid)
+		return
+	}
+	toolchain := &toolchainResponse.Items[0]
+	if toolchain.Container != nil && stringValue(toolchain.Container.GUID) == resourceGroupID {
+		err = fmt.Errorf("toolchain %s is already in resource group %s", guid, resourceGroupID)
+		return
+	}
+
+	result = &MoveToolchainResult{SourceGUID: guid, Tools: []ToolMove{}}
+	for i := range toolchain.Services {
+		result.Tools = append(result.Tools, planToolMove(&toolchain.Services[i]))
+	}
+	if moveToolchainOptions.DryRun != nil && *moveToolchainOptions.DryRun {
+		return
+	}
+
+	repository := stringValue(moveToolchainOptions.Repository)
+	if repository == "" && toolchain.Template != nil {
+		repository = stringValue(toolchain.Template.URL)
+	}
+	if repository == "" {
+		err = fmt.Errorf("toolchain %s has no template, set Repository", guid)
+		return
+	}
+	createToolchainOptions := openToolchain.NewCreateToolchainOptions(envID, repository)
+	createToolchainOptions.SetAutocreate(true)
+	createToolchainOptions.SetResourceGroupID(resourceGroupID)
+	createToolchainOptions.SetProperty("name", stringValue(toolchain.Name))
+	createToolchainOptions.SetHeaders(headers)
+	response, err = openToolchain.CreateToolchainWithContext(ctx, createToolchainOptions)
+	if err != nil {
+		return
+	}
+	result.TargetGUID = toolchainGUIDFromLocation(response.Headers.Get("Location"))
+	if result.TargetGUID == "" {
+		err = fmt.Errorf("error creating toolchain in resource group %s: no toolchain location returned", resourceGroupID)
+		return
+	}
+
+	// The template creates its own tool integrations, which are kept rather than duplicated
+	templateServices, _, err := openToolchain.getToolchainServices(ctx, region, result.TargetGUID, headers)
+	if err != nil {
+		return
+	}
+	templateTools := map[string]bool{}
+	for i := range templateServices {
+		templateTools[toolName(&templateServices[i])] = true
+	}
+
+	// Pipelines are recreated last, since their configuration references the other tool integrations
+	for _, pipelines := range []bool{false, true} {
+		for i := range result.Tools {
+			tool := &result.Tools[i]
+			node := ToolNode{Service: tool.Service}
+			if !tool.Movable || node.IsPipeline() != pipelines || templateTools[toolName(tool.Service)] {
+				continue
+			}
+			createServiceInstanceOptions := openToolchain.NewCreateServiceInstanceOptions(envID)
+			createServiceInstanceOptions.SetToolchainID(result.TargetGUID)
+			createServiceInstanceOptions.SetServiceID(stringValue(tool.Service.ServiceID))
+			createServiceInstanceOptions.SetParameters(tool.parameters)
+			createServiceInstanceOptions.SetHeaders(headers)
+			_, _, tool.Error = openToolchain.CreateServiceInstanceWithContext(ctx, createServiceInstanceOptions)
+		}
+	}
+
+	targetServices, _, err := openToolchain.getToolchainServices(ctx, region, result.TargetGUID, headers)
+	if err != nil {
+		return
+	}
+	targetInstanceIDs := map[string]string{}
+	for i := range targetServices {
+		targetInstanceIDs[toolName(&targetServices[i])] = stringValue(targetServices[i].InstanceID)
+	}
+	for i := range result.Tools {
+		tool := &result.Tools[i]
+		if !tool.Movable || tool.Error != nil {
+			continue
+		}
+		tool.NewInstanceID = targetInstanceIDs[toolName(tool.Service)]
+		if tool.NewInstanceID == "" {
+			tool.Error = fmt.Errorf("tool integration not found in toolchain %s", result.TargetGUID)
+			continue
+		}
+		node := ToolNode{Service: tool.Service}
+		if node.IsTektonPipeline() {
+			copyTektonPipelineConfigOptions := openToolchain.NewCopyTektonPipelineConfigOptions(stringValue(tool.Service.InstanceID), tool.NewInstanceID, region)
+			copyTektonPipelineConfigOptions.SetHeaders(headers)
+			var copyResult *CopyTektonPipelineConfigResult
+			copyResult, _, tool.Error = openToolchain.CopyTektonPipelineConfigWithContext(ctx, copyTektonPipelineConfigOptions)
+			if tool.Error != nil {
+				continue
+			}
+			tool.SecurePropertiesToSet = copyResult.SecurePropertiesToSet
+		}
+		tool.Moved = true
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		instanceIDs := []string{}
+		for _, tool := range failed {
+			instanceIDs = append(instanceIDs, stringValue(tool.Service.InstanceID))
+		}
+		err = fmt.Errorf("error moving tool integrations %s", strings.Join(instanceIDs, ", "))
+		return
+	}
+
+	if moveToolchainOptions.DeleteSource != nil && *moveToolchainOptions.DeleteSource && len(result.NotMovable()) == 0 {
+		deleteToolchainOptions := openToolchain.NewDeleteToolchainOptions(region, guid)
+		deleteToolchainOptions.SetHeaders(headers)
+		response, err = openToolchain.DeleteToolchainWithContext(ctx, deleteToolchainOptions)
+		if err != nil {
+			return
+		}
+		result.SourceDeleted = true
+	}
+	return
+}
+
+// MoveToolchainOptions : The MoveToolchain options.
+type MoveToolchainOptions struct {
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// GUID of the toolchain.
+	GUID *string `validate:"required,ne="`
+
+	// Environment ID.
+	EnvID *string `validate:"required"`
+
+	// The GUID of the resource group to move the toolchain to.
+	ResourceGroupID *string `validate:"required,ne="`
+
+	// The URL of the Git repository containing the template the toolchain is recreated from. Defaults to the template
+	// of the toolchain.
+	Repository *string
+
+	// Delete the moved toolchain once every tool integration was moved.
+	DeleteSource *bool
+
+	// Return the plan without creating anything.
+	DryRun *bool
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewMoveToolchainOptions : Instantiate MoveToolchainOptions
+func (*OpenToolchainV1) NewMoveToolchainOptions(region string, guid string, envID string, resourceGroupID string) *MoveToolchainOptions {
+	return &MoveToolchainOptions{
+		Region:          core.StringPtr(region),
+		GUID:            core.StringPtr(guid),
+		EnvID:           core.StringPtr(envID),
+		ResourceGroupID: core.StringPtr(resourceGroupID),
+	}
+}
+
+// SetRegion : Allow user to set Region
+func (options *MoveToolchainOptions) SetRegion(region string) *MoveToolchainOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetGUID : Allow user to set GUID
+func (options *MoveToolchainOptions) SetGUID(guid string) *MoveToolchainOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetEnvID : Allow user to set EnvID
+func (options *MoveToolchainOptions) SetEnvID(envID string) *MoveToolchainOptions {
+	options.EnvID = core.StringPtr(envID)
+	return options
+}
+
+// SetResourceGroupID : Allow user to set ResourceGroupID
+func (options *MoveToolchainOptions) SetResourceGroupID(resourceGroupID string) *MoveToolchainOptions {
+	options.ResourceGroupID = core.StringPtr(resourceGroupID)
+	return options
+}
+
+// SetRepository : Allow user to set Repository
+func (options *MoveToolchainOptions) SetRepository(repository string) *MoveToolchainOptions {
+	options.Repository = core.StringPtr(repository)
+	return options
+}
+
+// SetDeleteSource : Allow user to set DeleteSource
+func (options *MoveToolchainOptions) SetDeleteSource(deleteSource bool) *MoveToolchainOptions {
+	options.DeleteSource = core.BoolPtr(deleteSource)
+	return options
+}
+
+// SetDryRun : Allow user to set DryRun
+func (options *MoveToolchainOptions) SetDryRun(dryRun bool) *MoveToolchainOptions {
+	options.DryRun = core.BoolPtr(dryRun)
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *MoveToolchainOptions) SetHeaders(param map[string]string) *MoveToolchainOptions {
+	options.Headers = param
+	return options
+}
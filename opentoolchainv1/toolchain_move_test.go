@@ -0,0 +1,216 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`MoveToolchain`, func() {
+	var testServer *httptest.Server
+	var sourceServices string
+	var templateServices, created []string
+	var createdToolchain, patched, deleted bool
+	var toolchainIncludes []string
+
+	BeforeEach(func() {
+		sourceServices = `{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"name": "app", "repo_url": "https://github.com/org/app", "type": "link"}},
+			{"service_id": "pipeline", "instance_id": "pl-ci", "parameters": {"name": "ci", "type": "tekton"}},
+			{"service_id": "slack", "instance_id": "slack1", "parameters": {"name": "chat", "api_token": "xoxb"}},
+			{"service_id": "pipeline", "instance_id": "pl-classic", "parameters": {"name": "classic", "type": "classic"}},
+			{"service_id": "customtool", "instance_id": "custom1", "parameters": {"name": "custom", "foo_bar": "x"}}`
+		templateServices = nil
+		created = nil
+		toolchainIncludes = nil
+		createdToolchain, patched, deleted = false, false, false
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+				toolchainIncludes = append(toolchainIncludes, req.URL.Query().Get("include"))
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "my toolchain", "container": {"guid": "rg-old", "type": "resource_group_id"},
+					"template": {"url": "https://github.com/open-toolchain/simple-toolchain"}, "services": [%s]}]}`, sourceServices)
+			case req.Method == "POST" && req.URL.Path == "/cloud.ibm.com/devops/setup/deploy":
+				Expect(req.ParseForm()).To(Succeed())
+				Expect(req.PostForm.Get("resourceGroupId")).To(Equal("rg-new"))
+				Expect(req.PostForm.Get("repository")).To(Equal("https://github.com/open-toolchain/simple-toolchain"))
+				Expect(req.PostForm.Get("name")).To(Equal("my toolchain"))
+				createdToolchain = true
+				created = append(created, templateServices...)
+				res.Header().Set("Location", "https://cloud.ibm.com/devops/toolchains/tc2?env_id=ibm:yp:us-south")
+				res.WriteHeader(201)
+			case req.Method == "POST" && req.URL.Path == "/cloud.ibm.com/devops/service_instances":
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				Expect(body["toolchainId"]).To(Equal("tc2"))
+				parameters := body["parameters"].(map[string]interface{})
+				created = append(created, fmt.Sprintf(`{"service_id": "%s", "instance_id": "new-%s", "parameters": {"name": "%s", "type": "%s"}}`,
+					body["serviceId"], parameters["name"], parameters["name"], parameters["type"]))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"status": "ok"}`)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc2":
+				res.WriteHeader(200)
+				fmt.Fprintf(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc2", "name": "my toolchain", "services": [%s]}]}`, strings.Join(created, ","))
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/pl-ci":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl-ci", "name": "ci", "toolchainId": "tc1", "updated_at_timestamp": 1, "envProperties": [{"name": "apikey", "value": "********", "type": "SECURE"}],
+					"inputs": [{"type": "scm", "serviceInstanceId": "repo1", "scmSource": {"path": ".tekton", "branch": "main"}}]}`)
+			case req.Method == "GET" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/new-ci":
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "new-ci", "name": "ci", "toolchainId": "tc2", "updated_at_timestamp": 1, "envProperties": []}`)
+			case req.Method == "PATCH" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/tekton-pipelines/new-ci/config":
+				var body map[string]interface{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				Expect(body["inputs"].([]interface{})[0].(map[string]interface{})["serviceInstanceId"]).To(Equal("new-app"))
+				Expect(body["envProperties"]).To(BeEmpty())
+				patched = true
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "new-ci", "name": "ci", "toolchainId": "tc2", "envProperties": []}`)
+			case req.Method == "DELETE" && req.URL.Path == "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+				deleted = true
+				res.WriteHeader(204)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke MoveToolchain in dry-run mode`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		moveToolchainOptionsModel := openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", "rg-new")
+		moveToolchainOptionsModel.SetDryRun(true)
+		result, _, err := openToolchainService.MoveToolchain(moveToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(createdToolchain).To(BeFalse())
+		Expect(result.NotMovable()).To(HaveLen(3))
+
+		var report bytes.Buffer
+		Expect(result.Print(&report)).To(Succeed())
+		Expect(report.String()).To(Equal(`Toolchain tc1 -> (dry run)
+  githubconsolidated repo1: movable
+  pipeline pl-ci: movable
+  slack slack1: cannot move, secret parameters cannot be read back: api_token
+  pipeline pl-classic: cannot move, the configuration of classic pipelines cannot be copied
+  customtool custom1: cannot move, unsupported parameters: foo_bar
+`))
+	})
+	It(`Invoke MoveToolchain successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		moveToolchainOptionsModel := openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", "rg-new")
+		moveToolchainOptionsModel.SetDeleteSource(true)
+		result, _, err := openToolchainService.MoveToolchain(moveToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.TargetGUID).To(Equal("tc2"))
+		Expect(result.Failed()).To(BeEmpty())
+		// The repository is created before the pipeline referencing it
+		Expect(created).To(HaveLen(2))
+		Expect(created[0]).To(ContainSubstring("new-app"))
+		Expect(result.Tools[0].Moved).To(BeTrue())
+		Expect(result.Tools[0].NewInstanceID).To(Equal("new-app"))
+		Expect(result.Tools[1].NewInstanceID).To(Equal("new-ci"))
+		Expect(result.Tools[1].SecurePropertiesToSet).To(Equal([]string{"apikey"}))
+		Expect(patched).To(BeTrue())
+
+		var report bytes.Buffer
+		Expect(result.Print(&report)).To(Succeed())
+		Expect(report.String()).To(ContainSubstring("  pipeline pl-ci -> new-ci, set secure properties by hand: apikey\n"))
+
+		// The source toolchain is kept since some tool integrations were not moved
+		Expect(result.SourceDeleted).To(BeFalse())
+		Expect(deleted).To(BeFalse())
+	})
+	It(`Invoke MoveToolchain with tool integrations created by the template`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		templateServices = []string{`{"service_id": "githubconsolidated", "instance_id": "new-app", "parameters": {"name": "app", "type": "new"}}`}
+		moveToolchainOptionsModel := openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", "rg-new")
+		result, _, err := openToolchainService.MoveToolchain(moveToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.Failed()).To(BeEmpty())
+
+		// Only the pipeline is created, the repository created by the template is reused
+		Expect(created).To(HaveLen(2))
+		Expect(created[1]).To(ContainSubstring("new-ci"))
+		Expect(result.Tools[0].Moved).To(BeTrue())
+		Expect(result.Tools[0].NewInstanceID).To(Equal("new-app"))
+		Expect(result.Tools[1].NewInstanceID).To(Equal("new-ci"))
+		Expect(patched).To(BeTrue())
+	})
+	It(`Invoke MoveToolchain and delete the source toolchain`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		sourceServices = `{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"name": "app", "repo_url": "https://github.com/org/app", "type": "link"}}`
+		moveToolchainOptionsModel := openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", "rg-new")
+		moveToolchainOptionsModel.SetDeleteSource(true)
+		result, _, err := openToolchainService.MoveToolchain(moveToolchainOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.SourceDeleted).To(BeTrue())
+		Expect(deleted).To(BeTrue())
+	})
+	It(`Invoke MoveToolchain with error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		_, _, err := openToolchainService.MoveToolchain(openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", "rg-old"))
+		Expect(err).To(MatchError("toolchain tc1 is already in resource group rg-old"))
+		Expect(createdToolchain).To(BeFalse())
+		// The container is needed to check the resource group, the template to recreate the toolchain
+		Expect(toolchainIncludes).To(Equal([]string{"fields,container,template,services"}))
+
+		result, response, err := openToolchainService.MoveToolchain(openToolchainService.NewMoveToolchainOptions("us-south", "tc1", "ibm:yp:us-south", ""))
+		Expect(err).ToNot(BeNil())
+		Expect(result).To(BeNil())
+		Expect(response).To(BeNil())
+	})
+})
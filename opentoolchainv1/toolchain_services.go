@@ -0,0 +1,275 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// Constants associated with the Service.ServiceID property.
+// Service IDs of commonly used tool integrations.
+const (
+	ServiceIDGithubConsolidatedConst = "githubconsolidated"
+	ServiceIDGithubIntegratedConst   = "github_integrated"
+	ServiceIDGitlabConst             = "gitlab"
+	ServiceIDHostedgitConst          = "hostedgit"
+	ServiceIDBitbucketgitConst       = "bitbucketgit"
+	ServiceIDPipelineConst           = pipelineServiceID
+	ServiceIDSlackConst              = "slack"
+	ServiceIDKeyprotectConst         = "keyprotect"
+	ServiceIDSecretsManagerConst     = "secretsmanager"
+)
+
+// ListToolchainServices : List the tool integrations of a toolchain matching a filter
+// The toolchain is fetched with its services section and its tool integrations are filtered locally, the results
+// carry what is needed to fetch the full details of each tool integration with GetServiceInstance.
+func (openToolchain *OpenToolchainV1) ListToolchainServices(listToolchainServicesOptions *ListToolchainServicesOptions) (result *ToolchainServiceList, response *core.DetailedResponse, err error) {
+	return openToolchain.ListToolchainServicesWithContext(context.Background(), listToolchainServicesOptions)
+}
+
+// ListToolchainServicesWithContext is an alternate form of the ListToolchainServices method which supports a Context parameter
+func (openToolchain *OpenToolchainV1) ListToolchainServicesWithContext(ctx context.Context, listToolchainServicesOptions *ListToolchainServicesOptions) (result *ToolchainServiceList, response *core.DetailedResponse, err error) {
+	err = core.ValidateNotNil(listToolchainServicesOptions, "listToolchainServicesOptions cannot be nil")
+	if err != nil {
+		return
+	}
+	err = core.ValidateStruct(listToolchainServicesOptions, "listToolchainServicesOptions")
+	if err != nil {
+		return
+	}
+
+	guid := *listToolchainServicesOptions.GUID
+	services, response, err := openToolchain.getToolchainServices(ctx, *listToolchainServicesOptions.Region, guid, listToolchainServicesOptions.Headers)
+	if err != nil {
+		return
+	}
+
+	list := &ToolchainServiceList{ToolchainID: guid, Items: []ToolchainService{}}
+	for i := range services {
+		service := &services[i]
+		if !listToolchainServicesOptions.Filter.Matches(service) {
+			continue
+		}
+		toolchainService := ToolchainService{
+			ToolchainID: guid,
+			InstanceID:  stringValue(service.InstanceID),
+			ServiceID:   stringValue(service.ServiceID),
+			Name:        serviceName(service),
+			Service:     service,
+		}
+		if service.Status != nil {
+			toolchainService.State = stringValue(service.Status.State)
+		}
+		list.Items = append(list.Items, toolchainService)
+	}
+
+	result = list
+	return
+}
+
+// serviceName returns the name of a tool integration, taken from the "name" parameter or the toolchain binding.
+func serviceName(service *Service) string {
+	name, _ := service.Parameters["name"].(string)
+	if name == "" && service.ToolchainBinding != nil {
+		name = stringValue(service.ToolchainBinding.Name)
+	}
+	return name
+}
+
+// ServiceFilter : Criteria matched by tool integrations
+// Empty criteria match every tool integration.
+type ServiceFilter struct {
+	// Service IDs, e.g. ServiceIDGithubConsolidatedConst, any of which the tool integration must have.
+	ServiceIDs []string
+
+	// States, any of which the tool integration must be in, compared case-insensitively.
+	States []string
+
+	// Tags the tool integration must all have.
+	Tags []string
+
+	// Name of the tool integration, compared case-insensitively.
+	Name *string
+}
+
+// NewServiceFilter : Instantiate ServiceFilter
+func NewServiceFilter() *ServiceFilter {
+	return &ServiceFilter{}
+}
+
+// SetServiceIDs : Allow user to set ServiceIDs
+func (filter *ServiceFilter) SetServiceIDs(serviceIDs ...string) *ServiceFilter {
+	filter.ServiceIDs = serviceIDs
+	return filter
+}
+
+// SetStates : Allow user to set States
+func (filter *ServiceFilter) SetStates(states ...string) *ServiceFilter {
+	filter.States = states
+	return filter
+}
+
+// SetTags : Allow user to set Tags
+func (filter *ServiceFilter) SetTags(tags ...string) *ServiceFilter {
+	filter.Tags = tags
+	return filter
+}
+
+// SetName : Allow user to set Name
+func (filter *ServiceFilter) SetName(name string) *ServiceFilter {
+	filter.Name = core.StringPtr(name)
+	return filter
+}
+
+// Matches returns true if the tool integration matches every criteria of the filter, a nil filter matches everything
+func (filter *ServiceFilter) Matches(service *Service) bool {
+	if filter == nil {
+		return true
+	}
+	if len(filter.ServiceIDs) > 0 && !containsString(filter.ServiceIDs, stringValue(service.ServiceID), false) {
+		return false
+	}
+	if len(filter.States) > 0 {
+		state := ""
+		if service.Status != nil {
+			state = stringValue(service.Status.State)
+		}
+		if !containsString(filter.States, state, true) {
+			return false
+		}
+	}
+	for _, tag := range filter.Tags {
+		if !containsString(service.Tags, tag, false) {
+			return false
+		}
+	}
+	if filter.Name != nil && !strings.EqualFold(serviceName(service), *filter.Name) {
+		return false
+	}
+	return true
+}
+
+// containsString returns true if the value is one of the values, optionally ignoring case.
+func containsString(values []string, value string, ignoreCase bool) bool {
+	for _, v := range values {
+		if v == value || (ignoreCase && strings.EqualFold(v, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListToolchainServicesOptions : The ListToolchainServices options.
+type ListToolchainServicesOptions struct {
+	// Toolchain region.
+	Region *string `validate:"required,ne="`
+
+	// GUID of the toolchain.
+	GUID *string `validate:"required,ne="`
+
+	// Criteria the tool integrations must match, every tool integration is listed if nil.
+	Filter *ServiceFilter
+
+	// Allows users to set headers on API requests
+	Headers map[string]string
+}
+
+// NewListToolchainServicesOptions : Instantiate ListToolchainServicesOptions
+func (*OpenToolchainV1) NewListToolchainServicesOptions(region string, guid string) *ListToolchainServicesOptions {
+	return &ListToolchainServicesOptions{
+		Region: core.StringPtr(region),
+		GUID:   core.StringPtr(guid),
+	}
+}
+
+// SetRegion : Allow user to set Region
+func (options *ListToolchainServicesOptions) SetRegion(region string) *ListToolchainServicesOptions {
+	options.Region = core.StringPtr(region)
+	return options
+}
+
+// SetGUID : Allow user to set GUID
+func (options *ListToolchainServicesOptions) SetGUID(guid string) *ListToolchainServicesOptions {
+	options.GUID = core.StringPtr(guid)
+	return options
+}
+
+// SetFilter : Allow user to set Filter
+func (options *ListToolchainServicesOptions) SetFilter(filter *ServiceFilter) *ListToolchainServicesOptions {
+	options.Filter = filter
+	return options
+}
+
+// SetHeaders : Allow user to set Headers
+func (options *ListToolchainServicesOptions) SetHeaders(param map[string]string) *ListToolchainServicesOptions {
+	options.Headers = param
+	return options
+}
+
+// ToolchainServiceList : Tool integrations of a toolchain matching a ServiceFilter
+type ToolchainServiceList struct {
+	ToolchainID string
+
+	// Tool integrations in the order they are listed in Toolchain.Services.
+	Items []ToolchainService
+}
+
+// First returns the first tool integration of the list, or nil if the list is empty
+func (list *ToolchainServiceList) First() *ToolchainService {
+	if len(list.Items) == 0 {
+		return nil
+	}
+	return &list.Items[0]
+}
+
+// InstanceIDs returns the service instance IDs of the tool integrations
+func (list *ToolchainServiceList) InstanceIDs() []string {
+	instanceIDs := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		instanceIDs = append(instanceIDs, item.InstanceID)
+	}
+	return instanceIDs
+}
+
+// ToolchainService : Tool integration of a toolchain
+type ToolchainService struct {
+	ToolchainID string
+
+	InstanceID string
+
+	ServiceID string
+
+	// Name of the tool integration, taken from its "name" parameter or its toolchain binding.
+	Name string
+
+	State string
+
+	// Tool integration as listed by GetToolchain.
+	Service *Service
+}
+
+// NewGetServiceInstanceOptions : Instantiate GetServiceInstanceOptions fetching the full details of the tool integration
+func (toolchainService *ToolchainService) NewGetServiceInstanceOptions(envID string) *GetServiceInstanceOptions {
+	return &GetServiceInstanceOptions{
+		GUID:        core.StringPtr(toolchainService.InstanceID),
+		EnvID:       core.StringPtr(envID),
+		ToolchainID: core.StringPtr(toolchainService.ToolchainID),
+	}
+}
@@ -0,0 +1,113 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`ListToolchainServices`, func() {
+	var testServer *httptest.Server
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			res.Header().Set("Content-type", "application/json")
+			switch req.URL.Path {
+			case "/devops-api.us-south.devops.cloud.ibm.com/v1/toolchains/tc1":
+				Expect(req.URL.Query().Get("include")).To(Equal("services"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain", "services": [
+					{"service_id": "githubconsolidated", "instance_id": "repo1", "parameters": {"name": "app"}, "status": {"state": "configured"}, "tags": ["team:a", "prod"]},
+					{"service_id": "githubconsolidated", "instance_id": "repo2", "toolchain_binding": {"name": "Config"}, "status": {"state": "error"}, "tags": ["team:a"]},
+					{"service_id": "pipeline", "instance_id": "pl1", "parameters": {"name": "ci", "type": "tekton"}, "status": {"state": "configured"}},
+					{"service_id": "slack", "instance_id": "slack1"}]}]}`)
+			case "/cloud.ibm.com/devops/service_instances/repo2":
+				Expect(req.URL.Query().Get("env_id")).To(Equal("ibm:yp:us-south"))
+				Expect(req.URL.Query().Get("toolchainId")).To(Equal("tc1"))
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"serviceInstance": {"service_id": "githubconsolidated", "instance_id": "repo2"}}`)
+			default:
+				Fail("unexpected request " + req.Method + " " + req.URL.Path)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Invoke ListToolchainServices successfully`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		listToolchainServicesOptionsModel := openToolchainService.NewListToolchainServicesOptions("us-south", "tc1")
+		result, _, err := openToolchainService.ListToolchainServices(listToolchainServicesOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.InstanceIDs()).To(Equal([]string{"repo1", "repo2", "pl1", "slack1"}))
+
+		listToolchainServicesOptionsModel.SetFilter(opentoolchainv1.NewServiceFilter().SetServiceIDs(opentoolchainv1.ServiceIDGithubConsolidatedConst))
+		result, _, err = openToolchainService.ListToolchainServices(listToolchainServicesOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.InstanceIDs()).To(Equal([]string{"repo1", "repo2"}))
+		Expect(result.Items[1].Name).To(Equal("Config"))
+		Expect(result.Items[1].State).To(Equal("error"))
+
+		listToolchainServicesOptionsModel.SetFilter(opentoolchainv1.NewServiceFilter().SetStates("CONFIGURED").SetTags("team:a"))
+		result, _, err = openToolchainService.ListToolchainServices(listToolchainServicesOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.InstanceIDs()).To(Equal([]string{"repo1"}))
+
+		listToolchainServicesOptionsModel.SetFilter(opentoolchainv1.NewServiceFilter().SetName("config").SetTags("team:a"))
+		result, _, err = openToolchainService.ListToolchainServices(listToolchainServicesOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.First().InstanceID).To(Equal("repo2"))
+
+		// The results link to the full details of the tool integration
+		serviceInstance, _, err := openToolchainService.GetServiceInstance(result.First().NewGetServiceInstanceOptions("ibm:yp:us-south"))
+		Expect(err).To(BeNil())
+		Expect(*serviceInstance.ServiceInstance.InstanceID).To(Equal("repo2"))
+
+		listToolchainServicesOptionsModel.SetFilter(opentoolchainv1.NewServiceFilter().SetServiceIDs("gitlab"))
+		result, _, err = openToolchainService.ListToolchainServices(listToolchainServicesOptionsModel)
+		Expect(err).To(BeNil())
+		Expect(result.Items).To(BeEmpty())
+		Expect(result.First()).To(BeNil())
+	})
+	It(`Invoke ListToolchainServices with error`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+
+		result, response, err := openToolchainService.ListToolchainServices(openToolchainService.NewListToolchainServicesOptions("us-south", ""))
+		Expect(err).ToNot(BeNil())
+		Expect(response).To(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
@@ -0,0 +1,121 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	common "github.com/dariusbakunas/opentoolchain-go-sdk/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the tracer recording the spans of the service operations.
+const TracerName = "github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+
+// Attributes of the span recorded for an operation
+const (
+	RegionAttributeKey         = attribute.Key("opentoolchain.region")
+	ToolchainGUIDAttributeKey  = attribute.Key("opentoolchain.toolchain_guid")
+	PipelineGUIDAttributeKey   = attribute.Key("opentoolchain.pipeline_guid")
+	HTTPStatusCodeAttributeKey = attribute.Key("http.status_code")
+	RetryCountAttributeKey     = attribute.Key("opentoolchain.retry_count")
+)
+
+var regionHostPattern = regexp.MustCompile(`devops-api\.([^./]+)\.devops\.cloud\.ibm\.com`)
+
+// SetTracerProvider sets the provider of the tracer recording a span for every operation, nil selects the global
+// tracer provider
+func (openToolchain *OpenToolchainV1) SetTracerProvider(provider trace.TracerProvider) {
+	openToolchain.tracer = newTracer(provider)
+}
+
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(TracerName, trace.WithInstrumentationVersion(common.Version))
+}
+
+// tracingMiddleware records a span named after the operation ID, child of the span found in the request context,
+// and propagates the trace context to the service with the global propagator.
+func (openToolchain *OpenToolchainV1) tracingMiddleware(next RoundTripper) RoundTripper {
+	tracer := openToolchain.tracer
+	if tracer == nil {
+		tracer = newTracer(nil)
+	}
+	return RoundTripperFunc(func(operationID string, request *http.Request, result interface{}) (*core.DetailedResponse, error) {
+		ctx, span := tracer.Start(request.Context(), operationID,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(operationAttributes(request.URL)...))
+		defer span.End()
+
+		request = request.WithContext(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+		response, err := next.RoundTrip(operationID, request, result)
+		if response != nil && response.StatusCode != 0 {
+			span.SetAttributes(HTTPStatusCodeAttributeKey.Int(response.StatusCode))
+		}
+		if state := operationStateFromContext(ctx); state != nil {
+			span.SetAttributes(RetryCountAttributeKey.Int(state.retries()))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return response, err
+	})
+}
+
+// operationAttributes returns the region, toolchain and pipeline GUID attributes found in the URL of a request.
+// The region comes from the regional API host or the env_id query parameter, e.g. "ibm:yp:us-south".
+func operationAttributes(requestURL *url.URL) []attribute.KeyValue {
+	if requestURL == nil {
+		return nil
+	}
+	attributes := []attribute.KeyValue{}
+	query := requestURL.Query()
+
+	if match := regionHostPattern.FindStringSubmatch(requestURL.Host + requestURL.Path); match != nil {
+		attributes = append(attributes, RegionAttributeKey.String(match[1]))
+	} else if envID := query.Get("env_id"); envID != "" {
+		attributes = append(attributes, RegionAttributeKey.String(envID[strings.LastIndex(envID, ":")+1:]))
+	}
+
+	toolchainGUID := query.Get("toolchainId")
+	segments := strings.Split(strings.Trim(requestURL.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "toolchains":
+			toolchainGUID = segments[i+1]
+		case "tekton-pipelines":
+			attributes = append(attributes, PipelineGUIDAttributeKey.String(segments[i+1]))
+		}
+	}
+	if toolchainGUID != "" {
+		attributes = append(attributes, ToolchainGUIDAttributeKey.String(toolchainGUID))
+	}
+	return attributes
+}
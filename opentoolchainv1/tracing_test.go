@@ -0,0 +1,160 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe(`OpenTelemetry tracing`, func() {
+	var testServer *httptest.Server
+	var recorder *tracetest.SpanRecorder
+	var tracerProvider *sdktrace.TracerProvider
+	var traceparents []string
+	var patchRequests int
+
+	spanAttributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		attributes := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			attributes[kv.Key] = kv.Value
+		}
+		return attributes
+	}
+
+	BeforeEach(func() {
+		traceparents = nil
+		patchRequests = 0
+		recorder = tracetest.NewSpanRecorder()
+		tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		testServer = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			traceparents = append(traceparents, req.Header.Get("traceparent"))
+			res.Header().Set("Content-type", "application/json")
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/toolchains/missing"):
+				res.WriteHeader(404)
+				fmt.Fprint(res, `{"message": "not found"}`)
+			case strings.HasSuffix(req.URL.Path, "/config"):
+				patchRequests++
+				if patchRequests == 1 {
+					res.Header().Set("Retry-After", "0")
+					res.WriteHeader(429)
+					fmt.Fprint(res, `{"message": "too many requests"}`)
+					return
+				}
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"id": "pl1", "name": "n", "toolchainId": "tc1", "envProperties": []}`)
+			default:
+				res.WriteHeader(200)
+				fmt.Fprint(res, `{"total_results": 1, "items": [{"toolchain_guid": "tc1", "name": "toolchain"}]}`)
+			}
+		}))
+	})
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It(`Record a span for each operation`, func() {
+		previousPropagator := otel.GetTextMapPropagator()
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		defer otel.SetTextMapPropagator(previousPropagator)
+
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:            testServer.URL,
+			Authenticator:  &core.NoAuthAuthenticator{},
+			TracerProvider: tracerProvider,
+		})
+		Expect(serviceErr).To(BeNil())
+
+		ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+		_, _, err := openToolchainService.GetToolchainWithContext(ctx, openToolchainService.NewGetToolchainOptions("us-south", "tc1"))
+		Expect(err).To(BeNil())
+		parent.End()
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		span := spans[0]
+		Expect(span.Name()).To(Equal("GetToolchain"))
+		Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(span.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+		Expect(span.InstrumentationLibrary().Name).To(Equal(opentoolchainv1.TracerName))
+
+		attributes := spanAttributes(span)
+		Expect(attributes[opentoolchainv1.RegionAttributeKey].AsString()).To(Equal("us-south"))
+		Expect(attributes[opentoolchainv1.ToolchainGUIDAttributeKey].AsString()).To(Equal("tc1"))
+		Expect(attributes[opentoolchainv1.HTTPStatusCodeAttributeKey].AsInt64()).To(Equal(int64(200)))
+		Expect(attributes[opentoolchainv1.RetryCountAttributeKey].AsInt64()).To(Equal(int64(0)))
+		Expect(attributes).ToNot(HaveKey(opentoolchainv1.PipelineGUIDAttributeKey))
+
+		Expect(traceparents).To(HaveLen(1))
+		Expect(traceparents[0]).To(ContainSubstring(span.SpanContext().SpanID().String()))
+	})
+	It(`Record the retry count`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:           testServer.URL,
+			Authenticator: &core.NoAuthAuthenticator{},
+		})
+		Expect(serviceErr).To(BeNil())
+		openToolchainService.SetTracerProvider(tracerProvider)
+		openToolchainService.EnableRetries(2, 10*time.Millisecond)
+
+		_, _, err := openToolchainService.PatchTektonPipeline(openToolchainService.NewPatchTektonPipelineOptions("pl1", "eu-de"))
+		Expect(err).To(BeNil())
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("PatchTektonPipeline"))
+		attributes := spanAttributes(spans[0])
+		Expect(attributes[opentoolchainv1.RegionAttributeKey].AsString()).To(Equal("eu-de"))
+		Expect(attributes[opentoolchainv1.PipelineGUIDAttributeKey].AsString()).To(Equal("pl1"))
+		Expect(attributes[opentoolchainv1.HTTPStatusCodeAttributeKey].AsInt64()).To(Equal(int64(200)))
+		Expect(attributes[opentoolchainv1.RetryCountAttributeKey].AsInt64()).To(Equal(int64(1)))
+	})
+	It(`Record operation errors`, func() {
+		openToolchainService, serviceErr := opentoolchainv1.NewOpenToolchainV1(&opentoolchainv1.OpenToolchainV1Options{
+			URL:            testServer.URL,
+			Authenticator:  &core.NoAuthAuthenticator{},
+			TracerProvider: tracerProvider,
+		})
+		Expect(serviceErr).To(BeNil())
+
+		_, _, err := openToolchainService.GetToolchain(openToolchainService.NewGetToolchainOptions("us-south", "missing"))
+		Expect(err).ToNot(BeNil())
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spanAttributes(spans[0])[opentoolchainv1.HTTPStatusCodeAttributeKey].AsInt64()).To(Equal(int64(404)))
+	})
+})
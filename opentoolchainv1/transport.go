@@ -0,0 +1,179 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// SetHTTPClient sets the HTTP client used to send requests
+// Use this method rather than Service.SetHTTPClient so that the rate limiter stays in effect.
+func (openToolchain *OpenToolchainV1) SetHTTPClient(client *http.Client) {
+	openToolchain.Service.SetHTTPClient(client)
+	openToolchain.configureHTTPClient()
+}
+
+// SetRateLimiter sets the rate limiter applied to every request, nil disables rate limiting
+func (openToolchain *OpenToolchainV1) SetRateLimiter(limiter *RateLimiter) {
+	openToolchain.rateLimiter = limiter
+	openToolchain.configureHTTPClient()
+}
+
+// GetRateLimiter returns the rate limiter applied to every request
+func (openToolchain *OpenToolchainV1) GetRateLimiter() *RateLimiter {
+	return openToolchain.rateLimiter
+}
+
+// sdkTransport sends every HTTP attempt of an operation, including retries, on behalf of the SDK.
+type sdkTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (transport *sdkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	state := operationStateFromContext(req.Context())
+	if state != nil {
+		atomic.AddInt32(&state.attempts, 1)
+	}
+	if transport.limiter != nil {
+		start := time.Now()
+		waited, err := transport.limiter.wait(req.Context())
+		if waited && state != nil {
+			atomic.AddInt64(&state.rateLimitWait, int64(time.Since(start)))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	base := transport.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if transport.limiter != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(resp.Header); ok {
+			transport.limiter.Pause(d)
+		}
+	}
+	return resp, nil
+}
+
+// operationState holds what the SDK transport observed while sending the HTTP attempts of an operation.
+type operationState struct {
+	attempts int32
+
+	// Total time spent waiting for the rate limiter, in nanoseconds.
+	rateLimitWait int64
+}
+
+// retries returns the number of HTTP attempts made beyond the first one.
+func (state *operationState) retries() int {
+	if n := int(atomic.LoadInt32(&state.attempts)); n > 1 {
+		return n - 1
+	}
+	return 0
+}
+
+// operationStateContextKey is the context key of the operationState of an operation.
+type operationStateContextKey struct{}
+
+// withOperationState returns a context in which the SDK transport records the HTTP attempts of an operation.
+func withOperationState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationStateContextKey{}, new(operationState))
+}
+
+func operationStateFromContext(ctx context.Context) *operationState {
+	state, _ := ctx.Value(operationStateContextKey{}).(*operationState)
+	return state
+}
+
+// configureHTTPClient installs the SDK transport beneath the retry layer of the service HTTP client, so that it sees
+// every attempt. The client is copied rather than modified since it may be shared with clones of the service.
+func (openToolchain *OpenToolchainV1) configureHTTPClient() {
+	client := openToolchain.Service.Client
+	if client == nil {
+		return
+	}
+
+	wrap := func(inner *http.Client) *http.Client {
+		base := inner.Transport
+		if transport, ok := base.(*sdkTransport); ok {
+			base = transport.base
+		}
+		wrapped := *inner
+		wrapped.Transport = &sdkTransport{
+			base:    base,
+			limiter: openToolchain.rateLimiter,
+		}
+		return &wrapped
+	}
+
+	rt, ok := client.Transport.(*retryablehttp.RoundTripper)
+	if !ok || rt.Client == nil {
+		openToolchain.Service.Client = wrap(client)
+		return
+	}
+
+	httpClient := rt.Client.HTTPClient
+	if httpClient == nil {
+		httpClient = new(http.Client)
+	}
+	retryableClient := &retryablehttp.Client{
+		HTTPClient:      wrap(httpClient),
+		Logger:          rt.Client.Logger,
+		RetryWaitMin:    rt.Client.RetryWaitMin,
+		RetryWaitMax:    rt.Client.RetryWaitMax,
+		RetryMax:        rt.Client.RetryMax,
+		RequestLogHook:  rt.Client.RequestLogHook,
+		ResponseLogHook: rt.Client.ResponseLogHook,
+		CheckRetry:      rt.Client.CheckRetry,
+		Backoff:         rt.Client.Backoff,
+		ErrorHandler:    rt.Client.ErrorHandler,
+	}
+	outer := *client
+	outer.Transport = &retryablehttp.RoundTripper{Client: retryableClient}
+	openToolchain.Service.Client = &outer
+}
+
+// parseRetryAfter returns the wait requested by a Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,199 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Constants associated with the ScmEvent.Type property.
+const (
+	ScmEventTypePushConst              = "push"
+	ScmEventTypePullRequestConst       = "pull_request"
+	ScmEventTypePullRequestClosedConst = "pull_request_closed"
+)
+
+// Constants associated with the TektonPipelineTrigger.Type property.
+const (
+	TektonPipelineTriggerTypeScmConst     = "scm"
+	TektonPipelineTriggerTypeManualConst  = "manual"
+	TektonPipelineTriggerTypeTimerConst   = "timer"
+	TektonPipelineTriggerTypeGenericConst = "generic"
+)
+
+// ScmEvent : A Git event delivered to the triggers of a pipeline
+type ScmEvent struct {
+	// URL of the repository.
+	RepoURL string `json:"repo_url"`
+
+	// Branch pushed to, or the target branch of the pull request.
+	Branch string `json:"branch"`
+
+	// One of the ScmEventType constants.
+	Type string `json:"type"`
+
+	// Files changed by the event. Trigger configurations have no file filter, the files do not affect matching and
+	// are kept so that the simulation describes the whole event.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+}
+
+// TriggerSimulation : Triggers of a pipeline that would run for an SCM event
+type TriggerSimulation struct {
+	Event *ScmEvent `json:"event"`
+
+	// Results in the order the triggers are listed in the pipeline.
+	Results []TriggerMatch `json:"results"`
+}
+
+// TriggerMatch : Whether a trigger would run for an SCM event
+type TriggerMatch struct {
+	// Name of the trigger, or its ID if it has no name.
+	Trigger string `json:"trigger"`
+
+	Matches bool `json:"matches"`
+
+	// Reasons the trigger would not run, empty if it matches.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// SimulateTektonPipelineTriggers : Evaluate an SCM event against the triggers of a pipeline
+// A trigger runs if it is an enabled Git trigger whose repository URL, compared after normalization, is the one of the
+// event, whose branch is the one of the event or whose pattern matches it, and which listens to the type of the
+// event. Every unmet condition is reported. Patterns are globs where * matches within a path segment, ** across
+// segments and ? a single character; a trigger without branch nor pattern matches every branch. Without a pipeline or
+// an event, the simulation has no results. The toolchain graph of the pipeline is optional: when given, triggers
+// without URL use the repository of the tool integration they reference, as they do when the pipeline runs.
+func SimulateTektonPipelineTriggers(pipeline *TektonPipeline, event *ScmEvent, graph *ToolchainGraph) *TriggerSimulation {
+	simulation := &TriggerSimulation{Event: event, Results: []TriggerMatch{}}
+	if pipeline == nil || event == nil {
+		return simulation
+	}
+	for i := range pipeline.Triggers {
+		simulation.Results = append(simulation.Results, simulateTrigger(&pipeline.Triggers[i], event, graph))
+	}
+	return simulation
+}
+
+// simulateTrigger evaluates an SCM event against a trigger, resolving its repository through the graph if it has none.
+func simulateTrigger(trigger *TektonPipelineTrigger, event *ScmEvent, graph *ToolchainGraph) TriggerMatch {
+	match := TriggerMatch{Trigger: triggerName(trigger)}
+	if trigger.Disabled != nil && *trigger.Disabled {
+		match.Reasons = append(match.Reasons, "trigger is disabled")
+	}
+	if triggerType := stringValue(trigger.Type); triggerType != TektonPipelineTriggerTypeScmConst {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("%s trigger does not listen to Git events", triggerType))
+		return match
+	}
+
+	scmSource := trigger.ScmSource
+	if scmSource == nil {
+		scmSource = &TektonPipelineTriggerScmSource{}
+	}
+	if repoURL := graph.repoURL(scmSource.URL, trigger.ServiceInstanceID); repoURL == "" {
+		match.Reasons = append(match.Reasons, "no repository is configured")
+	} else if NormalizeRepoURL(repoURL) != NormalizeRepoURL(event.RepoURL) {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("repository %s is not %s", repoURL, event.RepoURL))
+	}
+	branch, pattern := stringValue(scmSource.Branch), stringValue(scmSource.Pattern)
+	switch {
+	case branch != "" && branch != event.Branch:
+		match.Reasons = append(match.Reasons, fmt.Sprintf("branch %s is not %s", event.Branch, branch))
+	case branch == "" && pattern != "" && !matchBranchPattern(pattern, event.Branch):
+		match.Reasons = append(match.Reasons, fmt.Sprintf("branch %s does not match pattern %s", event.Branch, pattern))
+	}
+	if !listensTo(trigger.Events, event.Type) {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("%s events are not enabled", event.Type))
+	}
+
+	match.Matches = len(match.Reasons) == 0
+	return match
+}
+
+// listensTo returns true if the events of a trigger include the event type.
+func listensTo(events *TektonPipelineTriggerEvents, eventType string) bool {
+	if events == nil {
+		return false
+	}
+	var enabled *bool
+	switch eventType {
+	case ScmEventTypePushConst:
+		enabled = events.Push
+	case ScmEventTypePullRequestConst:
+		enabled = events.PullRequest
+	case ScmEventTypePullRequestClosedConst:
+		enabled = events.PullRequestClosed
+	}
+	return enabled != nil && *enabled
+}
+
+// matchBranchPattern returns true if the branch matches the glob pattern.
+func matchBranchPattern(pattern string, branch string) bool {
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expression.WriteString("$")
+	matched, err := regexp.MatchString(expression.String(), branch)
+	return err == nil && matched
+}
+
+// triggerName returns the name of a trigger, or its ID if it has no name.
+func triggerName(trigger *TektonPipelineTrigger) string {
+	if name := stringValue(trigger.Name); name != "" {
+		return name
+	}
+	return stringValue(trigger.ID)
+}
+
+// Matching returns the triggers that would run
+func (simulation *TriggerSimulation) Matching() []TriggerMatch {
+	matching := []TriggerMatch{}
+	for _, result := range simulation.Results {
+		if result.Matches {
+			matching = append(matching, result)
+		}
+	}
+	return matching
+}
+
+// Print writes one line per trigger with the reasons it would not run
+func (simulation *TriggerSimulation) Print(w io.Writer) error {
+	for _, result := range simulation.Results {
+		status := "runs"
+		if !result.Matches {
+			status = "does not run, " + strings.Join(result.Reasons, ", ")
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", result.Trigger, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
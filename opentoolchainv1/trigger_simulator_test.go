@@ -0,0 +1,172 @@
+/**
+ * (C) Copyright IBM Corp. 2021.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentoolchainv1_test
+
+import (
+	"bytes"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/dariusbakunas/opentoolchain-go-sdk/opentoolchainv1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe(`SimulateTektonPipelineTriggers`, func() {
+	gitTrigger := func(name string, url string, branch string, pattern string, events *opentoolchainv1.TektonPipelineTriggerEvents) opentoolchainv1.TektonPipelineTrigger {
+		trigger := opentoolchainv1.TektonPipelineTrigger{
+			Name:          core.StringPtr(name),
+			Type:          core.StringPtr(opentoolchainv1.TektonPipelineTriggerTypeScmConst),
+			EventListener: core.StringPtr("listener"),
+			ScmSource:     &opentoolchainv1.TektonPipelineTriggerScmSource{URL: core.StringPtr(url)},
+			Events:        events,
+		}
+		if branch != "" {
+			trigger.ScmSource.Branch = core.StringPtr(branch)
+		}
+		if pattern != "" {
+			trigger.ScmSource.Pattern = core.StringPtr(pattern)
+		}
+		return trigger
+	}
+	push := &opentoolchainv1.TektonPipelineTriggerEvents{Push: core.BoolPtr(true)}
+	pullRequest := &opentoolchainv1.TektonPipelineTriggerEvents{PullRequest: core.BoolPtr(true), PullRequestClosed: core.BoolPtr(true)}
+
+	var pipeline *opentoolchainv1.TektonPipeline
+
+	BeforeEach(func() {
+		disabled := gitTrigger("disabled", "https://github.com/org/app", "main", "", push)
+		disabled.Disabled = core.BoolPtr(true)
+		pipeline = &opentoolchainv1.TektonPipeline{
+			Triggers: []opentoolchainv1.TektonPipelineTrigger{
+				gitTrigger("main", "https://github.com/org/app", "main", "", push),
+				gitTrigger("release", "https://github.com/Org/App.git", "", "release/*", push),
+				gitTrigger("any", "https://github.com/org/app", "", "", push),
+				gitTrigger("pr", "https://github.com/org/app", "main", "", pullRequest),
+				gitTrigger("other", "https://github.com/org/other", "", "", push),
+				disabled,
+				{ID: core.StringPtr("t7"), Type: core.StringPtr(opentoolchainv1.TektonPipelineTriggerTypeManualConst), EventListener: core.StringPtr("listener")},
+			},
+		}
+	})
+
+	It(`Invoke SimulateTektonPipelineTriggers successfully`, func() {
+		event := &opentoolchainv1.ScmEvent{
+			RepoURL:      "https://github.com/org/app",
+			Branch:       "release/1.0",
+			Type:         opentoolchainv1.ScmEventTypePushConst,
+			ChangedFiles: []string{"README.md"},
+		}
+		simulation := opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Results).To(HaveLen(7))
+		matching := simulation.Matching()
+		Expect(matching).To(HaveLen(2))
+		Expect(matching[0].Trigger).To(Equal("release"))
+		Expect(matching[1].Trigger).To(Equal("any"))
+
+		var report bytes.Buffer
+		Expect(simulation.Print(&report)).To(Succeed())
+		Expect(report.String()).To(Equal(`main: does not run, branch release/1.0 is not main
+release: runs
+any: runs
+pr: does not run, branch release/1.0 is not main, push events are not enabled
+other: does not run, repository https://github.com/org/other is not https://github.com/org/app
+disabled: does not run, trigger is disabled, branch release/1.0 is not main
+t7: does not run, manual trigger does not listen to Git events
+`))
+	})
+	It(`Invoke SimulateTektonPipelineTriggers for pull requests`, func() {
+		event := &opentoolchainv1.ScmEvent{
+			RepoURL: "https://github.com/org/app.git",
+			Branch:  "main",
+			Type:    opentoolchainv1.ScmEventTypePullRequestClosedConst,
+		}
+		simulation := opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Matching()).To(HaveLen(1))
+		Expect(simulation.Matching()[0].Trigger).To(Equal("pr"))
+		Expect(simulation.Results[0].Reasons).To(Equal([]string{"pull_request_closed events are not enabled"}))
+
+		// Single stars do not match across path segments, double stars do
+		pipeline.Triggers = []opentoolchainv1.TektonPipelineTrigger{
+			gitTrigger("single", "https://github.com/org/app", "", "feature/*", push),
+			gitTrigger("double", "https://github.com/org/app", "", "feature/**", push),
+			gitTrigger("question", "https://github.com/org/app", "", "v?.x", push),
+		}
+		event.Type = opentoolchainv1.ScmEventTypePushConst
+		event.Branch = "feature/a/b"
+		simulation = opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Results[0].Matches).To(BeFalse())
+		Expect(simulation.Results[0].Reasons).To(Equal([]string{"branch feature/a/b does not match pattern feature/*"}))
+		Expect(simulation.Results[1].Matches).To(BeTrue())
+		event.Branch = "v1.x"
+		simulation = opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Results[2].Matches).To(BeTrue())
+	})
+	It(`Invoke SimulateTektonPipelineTriggers with missing fields`, func() {
+		event := &opentoolchainv1.ScmEvent{
+			RepoURL: "https://github.com/org/app",
+			Branch:  "main",
+			Type:    opentoolchainv1.ScmEventTypePushConst,
+		}
+		Expect(opentoolchainv1.SimulateTektonPipelineTriggers(nil, event, nil).Results).To(BeEmpty())
+		simulation := opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, nil, nil)
+		Expect(simulation.Event).To(BeNil())
+		Expect(simulation.Results).To(BeEmpty())
+
+		// Triggers without events nor repository do not match
+		pipeline.Triggers = []opentoolchainv1.TektonPipelineTrigger{
+			{Name: core.StringPtr("bare"), Type: core.StringPtr(opentoolchainv1.TektonPipelineTriggerTypeScmConst), EventListener: core.StringPtr("listener")},
+		}
+		simulation = opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Results).To(HaveLen(1))
+		Expect(simulation.Results[0].Matches).To(BeFalse())
+		Expect(simulation.Results[0].Reasons).To(Equal([]string{"no repository is configured", "push events are not enabled"}))
+	})
+	It(`Invoke SimulateTektonPipelineTriggers with the repository of a tool integration`, func() {
+		event := &opentoolchainv1.ScmEvent{
+			RepoURL: "https://github.com/org/app",
+			Branch:  "main",
+			Type:    opentoolchainv1.ScmEventTypePushConst,
+		}
+		pipeline.Triggers = []opentoolchainv1.TektonPipelineTrigger{
+			{
+				Name:              core.StringPtr("tool"),
+				Type:              core.StringPtr(opentoolchainv1.TektonPipelineTriggerTypeScmConst),
+				EventListener:     core.StringPtr("listener"),
+				ServiceInstanceID: core.StringPtr("repo1"),
+				ScmSource:         &opentoolchainv1.TektonPipelineTriggerScmSource{Branch: core.StringPtr("main")},
+				Events:            push,
+			},
+		}
+		simulation := opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, nil)
+		Expect(simulation.Results[0].Reasons).To(Equal([]string{"no repository is configured"}))
+
+		graph := &opentoolchainv1.ToolchainGraph{
+			Tools: []opentoolchainv1.ToolNode{
+				{Service: &opentoolchainv1.Service{
+					InstanceID: core.StringPtr("repo1"),
+					Parameters: map[string]interface{}{"repo_url": "https://github.com/Org/App.git"},
+				}},
+			},
+		}
+		simulation = opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, graph)
+		Expect(simulation.Results[0].Matches).To(BeTrue())
+
+		event.RepoURL = "https://github.com/org/other"
+		simulation = opentoolchainv1.SimulateTektonPipelineTriggers(pipeline, event, graph)
+		Expect(simulation.Results[0].Reasons).To(Equal([]string{"repository https://github.com/Org/App.git is not https://github.com/org/other"}))
+	})
+})